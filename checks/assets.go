@@ -0,0 +1,374 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/misty-step/site-forge/report"
+)
+
+type assetsCheck struct{}
+
+func (assetsCheck) Name() string           { return "assets" }
+func (assetsCheck) Dependencies() []string { return nil }
+func (assetsCheck) Severity() Severity     { return SeverityError }
+
+func (assetsCheck) Run(ctx context.Context, env *Env) report.Result {
+	return CheckAssets(env)
+}
+
+// CheckAssets verifies all referenced assets in HTML files exist
+func CheckAssets(env *Env) report.AssetsResult {
+	distDir := env.Dir
+	result := report.AssetsResult{
+		Status: "PASS",
+	}
+
+	// Find all HTML files
+	htmlFiles, err := env.HTMLFiles()
+	if err != nil {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("Error finding HTML files: %v", err)
+		return result
+	}
+
+	if len(htmlFiles) == 0 {
+		result.Status = "FAIL"
+		result.Details = "No HTML files found in dist directory"
+		return result
+	}
+
+	// Extract and verify all assets
+	var missing []report.AssetFinding
+	totalAssets := 0
+	unique := make(map[string]bool)
+	resolver := newSiteResolver(distDir, env.Config.BasePath)
+	relPath := func(path string) string {
+		rel, err := filepath.Rel(distDir, path)
+		if err != nil {
+			return path
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	// Stylesheets are parsed once, however many pages link them
+	parsed := make(map[string]bool)
+	var verify func(base *url.URL, source string, ref assetRef)
+	verify = func(base *url.URL, source string, ref assetRef) {
+		// Resolve the URL the way the browser would; external URLs are not checked
+		u, local := resolver.resolveURL(base, ref.URL)
+		if !local {
+			return
+		}
+		totalAssets++
+
+		finding := report.AssetFinding{
+			URL:       ref.URL,
+			Source:    source,
+			Line:      ref.Line,
+			Column:    ref.Col,
+			Element:   ref.Element,
+			Attribute: ref.Attribute,
+		}
+		assetPath := resolver.filePath(u.Path)
+		if assetPath == "" {
+			unique[u.Path] = true
+			finding.Path = u.Path
+			finding.Reason = "outside the site's base path"
+			missing = append(missing, finding)
+			return
+		}
+		unique[assetPath] = true
+		finding.Path = relPath(assetPath)
+
+		info, err := os.Stat(assetPath)
+		switch {
+		case err != nil:
+			finding.Reason = "file not found"
+		case info.IsDir():
+			finding.Reason = "is a directory"
+		}
+		if finding.Reason != "" {
+			missing = append(missing, finding)
+			return
+		}
+
+		// URLs in a stylesheet are relative to the stylesheet, not the page
+		if strings.HasSuffix(assetPath, ".css") && !parsed[assetPath] {
+			parsed[assetPath] = true
+			data, err := os.ReadFile(assetPath)
+			if err != nil {
+				return
+			}
+			css := string(data)
+			for _, ref := range extractCSSURLs(css) {
+				line, col := advance(1, 1, css[:ref.Offset])
+				verify(u, finding.Path, assetRef{URL: ref.URL, Line: line, Col: col, Attribute: ref.Syntax})
+			}
+		}
+	}
+
+	for _, htmlFile := range htmlFiles {
+		assets, err := extractAssets(htmlFile)
+		if err != nil {
+			result.Status = "FAIL"
+			result.Details = fmt.Sprintf("Error parsing %s: %v", htmlFile, err)
+			return result
+		}
+
+		page := resolver.pageURL(htmlFile)
+		source := relPath(htmlFile)
+		for _, asset := range assets {
+			verify(resolver.documentBase(page, asset.Base), source, asset)
+		}
+	}
+
+	result.Total = totalAssets
+	result.Unique = len(unique)
+	result.Missing = missing
+
+	if len(missing) > 0 {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("Missing %d assets", len(missing))
+	} else {
+		result.Details = fmt.Sprintf("%d/%d assets verified (%d unique)", totalAssets, totalAssets, len(unique))
+	}
+
+	return result
+}
+
+// findHTMLFiles recursively finds all HTML files in a directory
+func findHTMLFiles(dir string) ([]string, error) {
+	var files []string
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			subFiles, err := findHTMLFiles(path)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, subFiles...)
+		} else if strings.HasSuffix(entry.Name(), ".html") || strings.HasSuffix(entry.Name(), ".htm") {
+			files = append(files, path)
+		}
+	}
+
+	return files, nil
+}
+
+// assetRef is a URL referenced by a page or stylesheet.
+type assetRef struct {
+	// URL is the value as written.
+	URL string
+	// Base is the href of the page's <base> element, if any.
+	Base string
+	// Line and Col locate the reference in its source file.
+	Line int
+	Col  int
+	// Element and Attribute name where the URL was found, such as "img"
+	// and "srcset". CSS references use the attribute "url()" or "@import"
+	// and, in stylesheets, no element.
+	Element   string
+	Attribute string
+}
+
+// assetAttrs lists, per element, the attributes holding a single URL that
+// the browser fetches.
+var assetAttrs = map[string][]string{
+	"img":    {"src"},
+	"source": {"src"},
+	"script": {"src"},
+	"video":  {"src", "poster"},
+	"audio":  {"src"},
+	"track":  {"src"},
+	"embed":  {"src"},
+	"object": {"data"},
+}
+
+// srcsetAttrs lists, per element, the attributes holding a srcset.
+var srcsetAttrs = map[string][]string{
+	"img":    {"srcset"},
+	"source": {"srcset"},
+	"link":   {"imagesrcset"},
+}
+
+// fetchedLinkRels are the <link rel> keywords whose href is fetched.
+var fetchedLinkRels = []string{
+	"stylesheet",
+	"icon",
+	"apple-touch-icon",
+	"apple-touch-icon-precomposed",
+	"mask-icon",
+	"manifest",
+	"preload",
+	"modulepreload",
+}
+
+// metaImageProps are the Open Graph and Twitter card properties whose
+// content is an image URL.
+var metaImageProps = []string{
+	"og:image",
+	"og:image:url",
+	"og:image:secure_url",
+	"twitter:image",
+	"twitter:image:src",
+}
+
+// extractAssets extracts the URLs an HTML file makes the browser fetch:
+// sources of images, media, scripts and embedded objects, srcset
+// candidates, fetched <link> relations, social card images and the url()
+// and @import references of inline styles
+func extractAssets(htmlFile string) ([]assetRef, error) {
+	data, err := os.ReadFile(htmlFile)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := scanElements(data)
+	base := baseHref(elements)
+
+	var assets []assetRef
+	for _, el := range elements {
+		add := func(attribute, u string) {
+			assets = append(assets, assetRef{
+				URL:       u,
+				Base:      base,
+				Line:      el.Line,
+				Col:       el.Col,
+				Element:   el.Data,
+				Attribute: attribute,
+			})
+		}
+
+		attrs := make(map[string]string, len(el.Attr))
+		for _, attr := range el.Attr {
+			attrs[attr.Key] = attr.Val
+		}
+
+		if style, ok := attrs["style"]; ok {
+			for _, ref := range extractCSSURLs(style) {
+				add("style", ref.URL)
+			}
+		}
+		for _, key := range assetAttrs[el.Data] {
+			if v := strings.TrimSpace(attrs[key]); v != "" {
+				add(key, v)
+			}
+		}
+		for _, key := range srcsetAttrs[el.Data] {
+			for _, u := range parseSrcset(attrs[key]) {
+				add(key, u)
+			}
+		}
+
+		switch el.Data {
+		case "style":
+			for _, ref := range extractCSSURLs(el.Text) {
+				line, col := advance(el.TextLine, el.TextCol, el.Text[:ref.Offset])
+				assets = append(assets, assetRef{
+					URL:       ref.URL,
+					Base:      base,
+					Line:      line,
+					Col:       col,
+					Element:   "style",
+					Attribute: ref.Syntax,
+				})
+			}
+		case "link":
+			if href := strings.TrimSpace(attrs["href"]); href != "" && hasAnyToken(attrs["rel"], fetchedLinkRels) {
+				add("href", href)
+			}
+		case "input":
+			if strings.EqualFold(attrs["type"], "image") && strings.TrimSpace(attrs["src"]) != "" {
+				add("src", strings.TrimSpace(attrs["src"]))
+			}
+		case "meta":
+			prop := attrs["property"]
+			if prop == "" {
+				prop = attrs["name"]
+			}
+			if content := strings.TrimSpace(attrs["content"]); content != "" && hasAnyToken(prop, metaImageProps) {
+				add("content", content)
+			}
+		}
+	}
+	return assets, nil
+}
+
+// hasAnyToken reports whether the space-separated, case-insensitive token
+// list value contains one of tokens.
+func hasAnyToken(value string, tokens []string) bool {
+	for _, field := range strings.Fields(value) {
+		for _, token := range tokens {
+			if strings.EqualFold(field, token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseSrcset returns the image candidate URLs of a srcset attribute,
+// following the HTML parsing algorithm: a URL runs up to the next
+// whitespace, so data URIs may contain commas, and descriptors run up to
+// the next comma outside parentheses.
+func parseSrcset(srcset string) []string {
+	var urls []string
+	i := 0
+	for i < len(srcset) {
+		for i < len(srcset) && (isHTMLSpace(srcset[i]) || srcset[i] == ',') {
+			i++
+		}
+		if i == len(srcset) {
+			break
+		}
+
+		start := i
+		for i < len(srcset) && !isHTMLSpace(srcset[i]) {
+			i++
+		}
+		u := srcset[start:i]
+
+		if strings.HasSuffix(u, ",") {
+			// A URL ending in commas has no descriptors
+			u = strings.TrimRight(u, ",")
+		} else {
+			depth := 0
+		descriptors:
+			for ; i < len(srcset); i++ {
+				switch srcset[i] {
+				case '(':
+					depth++
+				case ')':
+					if depth > 0 {
+						depth--
+					}
+				case ',':
+					if depth == 0 {
+						i++
+						break descriptors
+					}
+				}
+			}
+		}
+
+		if u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
@@ -0,0 +1,135 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/chromedp"
+)
+
+// Browser is a headless Chrome shared by the browser-based checks. Each
+// Run gets a tab of its own, with at most the configured number of tabs
+// open at once, so many captures cost one Chrome process rather than one
+// each.
+type Browser struct {
+	// Port is the remote debugging port of the browser, for tools such as
+	// Lighthouse that connect to a running Chrome.
+	Port int
+	// Version is the product and version of the browser, such as
+	// "HeadlessChrome/126.0.6478.126".
+	Version string
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	tabs    chan struct{}
+	userDir string
+}
+
+// StartBrowser launches the Chrome of tools, or the one chromedp finds,
+// allowing up to concurrency tabs at once. Call Close to stop it.
+func StartBrowser(tools Tools, concurrency int) (*Browser, error) {
+	userDir, err := os.MkdirTemp("", "site-forge-chrome-")
+	if err != nil {
+		return nil, err
+	}
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Headless,
+		chromedp.DisableGPU,
+		chromedp.NoSandbox,
+		chromedp.UserDataDir(userDir),
+	)
+	if tools.Chrome != "" {
+		opts = append(opts, chromedp.ExecPath(tools.Chrome))
+	}
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, cancelBrowser := chromedp.NewContext(allocCtx)
+	b := &Browser{
+		ctx: ctx,
+		cancel: func() {
+			cancelBrowser()
+			cancelAlloc()
+		},
+		tabs:    make(chan struct{}, max(concurrency, 1)),
+		userDir: userDir,
+	}
+
+	// The first run starts Chrome in the browser's own tab
+	if err := chromedp.Run(ctx, browserVersion(&b.Version)); err != nil {
+		b.Close()
+		return nil, err
+	}
+	if b.Port, err = devToolsPort(userDir); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// devToolsPort returns the remote debugging port Chrome wrote to the
+// DevToolsActivePort file of its user data directory.
+func devToolsPort(userDir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(userDir, "DevToolsActivePort"))
+	if err != nil {
+		return 0, fmt.Errorf("find debugging port: %v", err)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	port, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		return 0, fmt.Errorf("find debugging port: invalid DevToolsActivePort %q", line)
+	}
+	return port, nil
+}
+
+// Run performs actions in a new tab, waiting for a free one if the pool is
+// full, and closes the tab. Settings such as device emulation apply to
+// that tab only. The tab is closed early if ctx is done.
+func (b *Browser) Run(ctx context.Context, actions ...chromedp.Action) error {
+	select {
+	case b.tabs <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-b.tabs }()
+
+	tabCtx, cancel := chromedp.NewContext(b.ctx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	if err := chromedp.Run(tabCtx, actions...); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// Tabs returns the number of tabs that can be open at once.
+func (b *Browser) Tabs() int {
+	return cap(b.tabs)
+}
+
+// Close stops the browser and removes its user data directory.
+func (b *Browser) Close() error {
+	b.cancel()
+	return os.RemoveAll(b.userDir)
+}
+
+// browserVersion stores the product and version of the browser in product.
+func browserVersion(product *string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		_, p, _, _, _, err := browser.GetVersion().Do(ctx)
+		if err != nil {
+			return err
+		}
+		*product = p
+		return nil
+	})
+}
@@ -0,0 +1,176 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+	"github.com/misty-step/site-forge/report"
+)
+
+type buildCheck struct{}
+
+func (buildCheck) Name() string           { return "build" }
+func (buildCheck) Dependencies() []string { return nil }
+func (buildCheck) Severity() Severity     { return SeverityError }
+
+func (buildCheck) Run(ctx context.Context, env *Env) report.Result {
+	return CheckBuild(env)
+}
+
+// CheckBuild verifies the HTML build is valid
+func CheckBuild(env *Env) report.BuildResult {
+	distDir := env.Dir
+	result := report.BuildResult{
+		Status: "PASS",
+	}
+
+	// Check for index.html
+	indexPath := filepath.Join(distDir, "index.html")
+	if _, err := os.Stat(indexPath); os.IsNotExist(err) {
+		result.Status = "FAIL"
+		result.Details = "index.html not found"
+		return result
+	}
+
+	htmlFiles, err := env.HTMLFiles()
+	if err != nil {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("Error finding HTML files: %v", err)
+		return result
+	}
+	result.Pages = len(htmlFiles)
+
+	// Validate every page that is not exempt
+	checked := 0
+	for _, htmlFile := range htmlFiles {
+		rel, _ := filepath.Rel(distDir, htmlFile)
+		rel = filepath.ToSlash(rel)
+		if env.Config.BuildExempt(rel) {
+			continue
+		}
+		checked++
+
+		problems, err := checkPage(htmlFile)
+		if err != nil {
+			problems = []string{err.Error()}
+		}
+		if len(problems) > 0 {
+			result.Findings = append(result.Findings, report.PageFindings{
+				Page:     rel,
+				Problems: problems,
+			})
+		}
+	}
+
+	if len(result.Findings) > 0 {
+		result.Status = "FAIL"
+		result.Details = describeFindings(result.Findings, checked)
+		return result
+	}
+
+	result.Details = fmt.Sprintf("Valid HTML, %d page(s), meta tags present", result.Pages)
+	return result
+}
+
+// checkPage parses an HTML file and returns its structural problems.
+func checkPage(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %v", err)
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("HTML parse error: %v", err)
+	}
+
+	// Check for basic structure
+	hasHTML := false
+	hasHead := false
+	hasBody := false
+	hasTitle := false
+	hasDescription := false
+	hasOgTitle := false
+
+	var check func(*html.Node)
+	check = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "html":
+				hasHTML = true
+			case "head":
+				hasHead = true
+			case "body":
+				hasBody = true
+			case "title":
+				hasTitle = true
+			case "meta":
+				var name, prop, content string
+				for _, attr := range n.Attr {
+					if attr.Key == "name" {
+						name = attr.Val
+					}
+					if attr.Key == "property" {
+						prop = attr.Val
+					}
+					if attr.Key == "content" {
+						content = attr.Val
+					}
+				}
+				if name == "description" && content != "" {
+					hasDescription = true
+				}
+				if prop == "og:title" && content != "" {
+					hasOgTitle = true
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			check(c)
+		}
+	}
+
+	check(doc)
+
+	// Validate structure
+	var problems []string
+	if !hasHTML {
+		problems = append(problems, "missing <html> tag")
+	}
+	if !hasHead {
+		problems = append(problems, "missing <head> tag")
+	}
+	if !hasBody {
+		problems = append(problems, "missing <body> tag")
+	}
+	if !hasTitle {
+		problems = append(problems, "missing <title> tag")
+	}
+	if !hasDescription {
+		problems = append(problems, "missing meta description")
+	}
+	if !hasOgTitle {
+		problems = append(problems, "missing og:title meta tag")
+	}
+	return problems, nil
+}
+
+// describeFindings summarizes page findings in one line, naming the first
+// few pages with problems.
+func describeFindings(findings []report.PageFindings, checked int) string {
+	const maxListed = 3
+
+	var parts []string
+	for i, f := range findings {
+		if i == maxListed {
+			parts = append(parts, fmt.Sprintf("and %d more", len(findings)-maxListed))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", f.Page, strings.Join(f.Problems, ", ")))
+	}
+	return fmt.Sprintf("%d/%d page(s) invalid - %s", len(findings), checked, strings.Join(parts, "; "))
+}
@@ -0,0 +1,246 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/internal/server"
+	"github.com/misty-step/site-forge/report"
+)
+
+// Severity controls how a failing check affects the overall result.
+type Severity string
+
+const (
+	// SeverityError fails the overall run when the check fails.
+	SeverityError Severity = "error"
+	// SeverityWarning reports the failure without failing the overall run.
+	SeverityWarning Severity = "warning"
+)
+
+// Check is a single verification step in the pipeline.
+type Check interface {
+	// Name is the unique key of the check in the report.
+	Name() string
+	// Dependencies lists the checks that must pass before this one runs.
+	Dependencies() []string
+	// Severity is the default severity of a failure.
+	Severity() Severity
+	// Run executes the check against env and returns its result.
+	Run(ctx context.Context, env *Env) report.Result
+}
+
+// Env is the input shared by all checks in a run.
+type Env struct {
+	Dir    string
+	Config *config.Config
+
+	// Results holds the results of the checks that have already run.
+	Results report.ReportChecks
+
+	mu      sync.Mutex
+	server  *server.Server
+	tools   *Tools
+	browser *Browser
+}
+
+// NewEnv returns an Env for dir with the default configuration.
+func NewEnv(dir string) *Env {
+	return &Env{
+		Dir:     dir,
+		Config:  config.Default(),
+		Results: report.ReportChecks{},
+	}
+}
+
+// HTMLFiles returns the HTML files of the site that are not excluded by
+// the ignore patterns of the config.
+func (e *Env) HTMLFiles() ([]string, error) {
+	files, err := findHTMLFiles(e.Dir)
+	if err != nil {
+		return nil, err
+	}
+	kept := files[:0]
+	for _, f := range files {
+		rel, err := filepath.Rel(e.Dir, f)
+		if err != nil || !e.Config.Ignored(rel) {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
+
+// Server returns the static server for the site, starting it on first use.
+// Browser-based checks share it, so they all see the site as it would be
+// served in production. Call Close when the run is over.
+func (e *Env) Server() (*server.Server, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.server == nil {
+		s, err := server.Start(e.Dir, server.Options{BasePath: e.Config.BasePath})
+		if err != nil {
+			return nil, fmt.Errorf("start static server: %v", err)
+		}
+		e.server = s
+	}
+	return e.server, nil
+}
+
+// Tools returns the external programs the browser-based checks run,
+// resolved from the config and the environment on first use.
+func (e *Env) Tools() Tools {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tools == nil {
+		t := ResolveTools(e.Config.Tools)
+		e.tools = &t
+	}
+	return *e.tools
+}
+
+// Browser returns the headless Chrome shared by the browser-based checks,
+// starting it on first use with browser.concurrency tabs. Call Close when
+// the run is over.
+func (e *Env) Browser() (*Browser, error) {
+	tools := e.Tools()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.browser == nil {
+		b, err := StartBrowser(tools, e.Config.Browser.Concurrency)
+		if err != nil {
+			return nil, fmt.Errorf("start browser: %v", err)
+		}
+		e.browser = b
+	}
+	return e.browser, nil
+}
+
+// Close stops the static server and the browser, if they were started.
+func (e *Env) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var err error
+	if e.browser != nil {
+		err = e.browser.Close()
+		e.browser = nil
+	}
+	if e.server != nil {
+		if serr := e.server.Close(); serr != nil {
+			err = serr
+		}
+		e.server = nil
+	}
+	return err
+}
+
+// Registry holds the set of known checks.
+type Registry struct {
+	checks []Check
+	byName map[string]Check
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]Check)}
+}
+
+// Register adds c to the registry. Names must be unique.
+func (r *Registry) Register(c Check) error {
+	if c.Name() == "" {
+		return fmt.Errorf("check has no name")
+	}
+	if _, ok := r.byName[c.Name()]; ok {
+		return fmt.Errorf("check %q already registered", c.Name())
+	}
+	r.checks = append(r.checks, c)
+	r.byName[c.Name()] = c
+	return nil
+}
+
+// Get returns the check registered under name.
+func (r *Registry) Get(name string) (Check, bool) {
+	c, ok := r.byName[name]
+	return c, ok
+}
+
+// ValidateConfig reports check names in cfg that are not registered.
+func (r *Registry) ValidateConfig(cfg *config.Config) error {
+	var unknown []string
+	for name := range cfg.Checks {
+		if _, ok := r.byName[name]; !ok {
+			unknown = append(unknown, fmt.Sprintf("checks.%s: unknown check %q", name, name))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return &config.ValidationError{Problems: unknown}
+	}
+	return nil
+}
+
+// Ordered returns the checks sorted so that every check comes after its
+// dependencies. Checks without ordering constraints keep registration order.
+func (r *Registry) Ordered() ([]Check, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(r.checks))
+	ordered := make([]Check, 0, len(r.checks))
+
+	var visit func(c Check) error
+	visit = func(c Check) error {
+		switch state[c.Name()] {
+		case visiting:
+			return fmt.Errorf("dependency cycle at check %q", c.Name())
+		case done:
+			return nil
+		}
+		state[c.Name()] = visiting
+		for _, dep := range c.Dependencies() {
+			d, ok := r.byName[dep]
+			if !ok {
+				return fmt.Errorf("check %q depends on unknown check %q", c.Name(), dep)
+			}
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		state[c.Name()] = done
+		ordered = append(ordered, c)
+		return nil
+	}
+
+	for _, c := range r.checks {
+		if err := visit(c); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Default is the registry used by the site-forge CLI.
+var Default = NewRegistry()
+
+// Register adds c to the default registry. It panics if the name is taken,
+// so it is meant to be called from init functions.
+func Register(c Check) {
+	if err := Default.Register(c); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	Register(assetsCheck{})
+	Register(buildCheck{})
+	Register(linksCheck{})
+	Register(lighthouseCheck{})
+	Register(screenshotsCheck{})
+	Register(visualCheck{})
+	Register(visionCheck{})
+}
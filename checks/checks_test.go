@@ -0,0 +1,1587 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+func TestFindHTMLFiles(t *testing.T) {
+	// Create a temp directory structure
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Create test files
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte("<html></html>"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "subdir"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "subdir", "about.html"), []byte("<html></html>"), 0644)
+
+	files, err := findHTMLFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("findHTMLFiles failed: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Errorf("Expected 2 HTML files, got %d", len(files))
+	}
+}
+
+func TestExtractAssets(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head>
+	<link rel="stylesheet" href="style.css">
+	<link rel="icon" href="favicon.ico">
+</head>
+<body>
+	<img src="hero.jpg" alt="Hero">
+	<img src="images/logo.svg" alt="Logo">
+	<script src="app.js"></script>
+	<picture>
+		<source srcset="banner.webp" type="image/webp">
+		<source srcset="banner.jpg" type="image/jpeg">
+		<img src="banner-fallback.jpg" alt="Banner">
+	</picture>
+</body>
+</html>`
+
+	htmlFile := filepath.Join(tmpDir, "index.html")
+	if err := os.WriteFile(htmlFile, []byte(htmlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create the referenced files
+	os.MkdirAll(filepath.Join(tmpDir, "images"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "style.css"), []byte("body {}"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "favicon.ico"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "hero.jpg"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "images", "logo.svg"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "app.js"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "banner.webp"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "banner.jpg"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "banner-fallback.jpg"), []byte{}, 0644)
+
+	assets, err := extractAssets(htmlFile)
+	if err != nil {
+		t.Fatalf("extractAssets failed: %v", err)
+	}
+
+	expected := []string{
+		"style.css",
+		"favicon.ico",
+		"hero.jpg",
+		"images/logo.svg",
+		"app.js",
+		"banner.webp",
+		"banner.jpg",
+		"banner-fallback.jpg",
+	}
+
+	if len(assets) != len(expected) {
+		t.Errorf("Expected %d assets, got %d", len(expected), len(assets))
+		t.Logf("Got: %v", assets)
+	}
+}
+
+func TestCheckAssets(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Create HTML with valid assets
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head><link rel="stylesheet" href="style.css"></head>
+<body><img src="hero.jpg"><script src="app.js"></script></body>
+</html>`
+
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(htmlContent), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "style.css"), []byte("body {}"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "app.js"), []byte("console.log()"), 0644)
+	// Note: hero.jpg is missing
+
+	result := CheckAssets(NewEnv(tmpDir))
+
+	if result.Status != "FAIL" {
+		t.Errorf("Expected FAIL status for missing asset, got %s", result.Status)
+	}
+
+	if len(result.Missing) != 1 || result.Missing[0].URL != "hero.jpg" {
+		t.Errorf("Expected missing hero.jpg, got %v", result.Missing)
+	}
+}
+
+func TestCheckAssetsAllValid(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head><link rel="stylesheet" href="style.css"></head>
+<body><img src="hero.jpg"><script src="app.js"></script></body>
+</html>`
+
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(htmlContent), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "style.css"), []byte("body {}"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "app.js"), []byte("console.log()"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "hero.jpg"), []byte{}, 0644)
+
+	result := CheckAssets(NewEnv(tmpDir))
+
+	if result.Status != "PASS" {
+		t.Errorf("Expected PASS status, got %s: %s", result.Status, result.Details)
+	}
+
+	if result.Total != 3 { // style.css, hero.jpg, app.js
+		t.Errorf("Expected 3 assets, got %d", result.Total)
+	}
+}
+
+func TestCheckBuild(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Test missing index.html
+	result := CheckBuild(NewEnv(tmpDir))
+	if result.Status != "FAIL" {
+		t.Errorf("Expected FAIL for missing index.html, got %s", result.Status)
+	}
+
+	// Test valid HTML
+	validHTML := `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<title>Test Site</title>
+	<meta name="description" content="A test site">
+	<meta property="og:title" content="Test Site">
+</head>
+<body><h1>Hello</h1></body>
+</html>`
+
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(validHTML), 0644)
+
+	result = CheckBuild(NewEnv(tmpDir))
+	if result.Status != "PASS" {
+		t.Errorf("Expected PASS for valid HTML, got %s: %s", result.Status, result.Details)
+	}
+
+	if result.Pages != 1 {
+		t.Errorf("Expected 1 page, got %d", result.Pages)
+	}
+}
+
+func TestCheckBuildMissingMeta(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// HTML missing meta tags
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head><title>Test</title></head>
+<body><h1>Hello</h1></body>
+</html>`
+
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(htmlContent), 0644)
+
+	result := CheckBuild(NewEnv(tmpDir))
+	if result.Status != "FAIL" {
+		t.Errorf("Expected FAIL for missing meta tags, got %s", result.Status)
+	}
+}
+
+type fakeCheck struct {
+	name   string
+	deps   []string
+	status string
+	ran    *[]string
+}
+
+func (f fakeCheck) Name() string           { return f.name }
+func (f fakeCheck) Dependencies() []string { return f.deps }
+func (f fakeCheck) Severity() Severity     { return SeverityError }
+
+func (f fakeCheck) Run(ctx context.Context, env *Env) report.Result {
+	*f.ran = append(*f.ran, f.name)
+	return report.BasicResult{Status: f.status}
+}
+
+func TestRegistryOrdered(t *testing.T) {
+	var ran []string
+	reg := NewRegistry()
+	reg.Register(fakeCheck{name: "vision", deps: []string{"screenshots"}, ran: &ran})
+	reg.Register(fakeCheck{name: "assets", ran: &ran})
+	reg.Register(fakeCheck{name: "screenshots", ran: &ran})
+
+	if err := reg.Register(fakeCheck{name: "assets", ran: &ran}); err == nil {
+		t.Error("Expected error registering duplicate check")
+	}
+
+	ordered, err := reg.Ordered()
+	if err != nil {
+		t.Fatalf("Ordered failed: %v", err)
+	}
+	var names []string
+	for _, c := range ordered {
+		names = append(names, c.Name())
+	}
+	expected := []string{"screenshots", "vision", "assets"}
+	for i := range expected {
+		if i >= len(names) || names[i] != expected[i] {
+			t.Fatalf("Expected order %v, got %v", expected, names)
+		}
+	}
+
+	reg.Register(fakeCheck{name: "orphan", deps: []string{"missing"}, ran: &ran})
+	if _, err := reg.Ordered(); err == nil {
+		t.Error("Expected error for unknown dependency")
+	}
+}
+
+func TestRunnerStopsOnFailure(t *testing.T) {
+	var ran []string
+	reg := NewRegistry()
+	reg.Register(fakeCheck{name: "assets", status: report.StatusPass, ran: &ran})
+	reg.Register(fakeCheck{name: "build", status: report.StatusFail, ran: &ran})
+	reg.Register(fakeCheck{name: "lighthouse", status: report.StatusPass, ran: &ran})
+
+	r := report.NewReport("dist")
+	runner := &Runner{Registry: reg, FailFast: true}
+	if err := runner.Run(context.Background(), NewEnv("dist"), r); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if r.Overall != report.StatusFail {
+		t.Errorf("Expected overall FAIL, got %s", r.Overall)
+	}
+	if len(ran) != 2 {
+		t.Errorf("Expected 2 checks to run, got %v", ran)
+	}
+	if got := r.Checks["lighthouse"].CheckStatus(); got != report.StatusSkip {
+		t.Errorf("Expected lighthouse SKIP, got %s", got)
+	}
+}
+
+func TestRunnerKeepsGoing(t *testing.T) {
+	var ran []string
+	reg := NewRegistry()
+	reg.Register(fakeCheck{name: "assets", status: report.StatusFail, ran: &ran})
+	reg.Register(fakeCheck{name: "lighthouse", status: report.StatusPass, ran: &ran})
+	reg.Register(fakeCheck{name: "screenshots", status: report.StatusSkip, ran: &ran})
+	reg.Register(fakeCheck{name: "vision", deps: []string{"screenshots"}, status: report.StatusPass, ran: &ran})
+
+	r := report.NewReport("dist")
+	runner := &Runner{Registry: reg}
+	if err := runner.Run(context.Background(), NewEnv("dist"), r); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if r.Overall != report.StatusFail {
+		t.Errorf("Expected overall FAIL, got %s", r.Overall)
+	}
+	if len(ran) != 3 {
+		t.Errorf("Expected 3 checks to run, got %v", ran)
+	}
+	if got := r.Checks["vision"].CheckStatus(); got != report.StatusBlocked {
+		t.Errorf("Expected vision BLOCKED, got %s", got)
+	}
+	if len(r.Names()) != 4 {
+		t.Errorf("Expected all 4 checks in report, got %v", r.Names())
+	}
+}
+
+func TestRunnerConfigOverrides(t *testing.T) {
+	var ran []string
+	reg := NewRegistry()
+	reg.Register(fakeCheck{name: "assets", status: report.StatusPass, ran: &ran})
+	reg.Register(fakeCheck{name: "lighthouse", status: report.StatusFail, ran: &ran})
+	reg.Register(fakeCheck{name: "vision", status: report.StatusFail, ran: &ran})
+
+	disabled := false
+	env := NewEnv("dist")
+	env.Config.Checks = map[string]config.CheckConfig{
+		"lighthouse": {Severity: "warning"},
+		"vision":     {Enabled: &disabled},
+	}
+	if err := reg.ValidateConfig(env.Config); err != nil {
+		t.Fatalf("ValidateConfig failed: %v", err)
+	}
+
+	r := report.NewReport("dist")
+	runner := &Runner{Registry: reg}
+	if err := runner.Run(context.Background(), env, r); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if r.Overall != report.StatusPass {
+		t.Errorf("Expected overall PASS with lighthouse as warning, got %s", r.Overall)
+	}
+	if got := r.Checks["vision"].CheckStatus(); got != report.StatusSkip {
+		t.Errorf("Expected disabled vision to be SKIP, got %s", got)
+	}
+
+	env.Config.Checks["lighthous"] = config.CheckConfig{}
+	if err := reg.ValidateConfig(env.Config); err == nil {
+		t.Error("Expected error for unknown check name")
+	}
+}
+
+func TestCheckBuildAllPages(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	validHTML := `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<title>Test Site</title>
+	<meta name="description" content="A test site">
+	<meta property="og:title" content="Test Site">
+</head>
+<body><h1>Hello</h1></body>
+</html>`
+	aboutHTML := `<!DOCTYPE html>
+<html>
+<head><title>About</title><meta property="og:title" content="About"></head>
+<body><h1>About</h1></body>
+</html>`
+
+	os.MkdirAll(filepath.Join(tmpDir, "about"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "fragments"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(validHTML), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "about", "index.html"), []byte(aboutHTML), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "fragments", "nav.html"), []byte("<nav></nav>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "google1234.html"), []byte("google-site-verification"), 0644)
+
+	env := NewEnv(tmpDir)
+	env.Config.Build.Exempt = []string{"fragments/", "google*.html"}
+
+	result := CheckBuild(env)
+	if result.Status != "FAIL" {
+		t.Fatalf("Expected FAIL for about page without description, got %s", result.Status)
+	}
+	if result.Pages != 4 {
+		t.Errorf("Expected 4 pages, got %d", result.Pages)
+	}
+	if len(result.Findings) != 1 || result.Findings[0].Page != "about/index.html" {
+		t.Fatalf("Expected one finding for about/index.html, got %+v", result.Findings)
+	}
+	if problems := result.Findings[0].Problems; len(problems) != 1 || problems[0] != "missing meta description" {
+		t.Errorf("Expected missing meta description, got %v", problems)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "about", "index.html"), []byte(validHTML), 0644)
+	result = CheckBuild(env)
+	if result.Status != "PASS" {
+		t.Errorf("Expected PASS with exempt fragments, got %s: %s", result.Status, result.Details)
+	}
+}
+
+func TestCheckAssetsResolvesLikeBrowser(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// A nested page mixing root-relative, page-relative and external URLs
+	pageHTML := `<!DOCTYPE html>
+<html>
+<head>
+	<link rel="stylesheet" href="/css/style.css?v=3">
+	<link rel="icon" href="https://cdn.example.com/favicon.ico">
+</head>
+<body>
+	<img src="photo%20one.jpg#top">
+	<img src="../shared/logo.svg">
+	<img src="/img/missing.png">
+</body>
+</html>`
+	baseHTML := `<!DOCTYPE html>
+<html>
+<head><base href="/assets/"></head>
+<body><script src="app.js"></script></body>
+</html>`
+
+	os.MkdirAll(filepath.Join(tmpDir, "blog", "post"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "blog", "shared"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "css"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "assets"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "blog", "post", "index.html"), []byte(pageHTML), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "blog", "post", "photo one.jpg"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "blog", "shared", "logo.svg"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "css", "style.css"), []byte("body {}"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(baseHTML), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "assets", "app.js"), []byte{}, 0644)
+
+	result := CheckAssets(NewEnv(tmpDir))
+	if result.Status != "FAIL" {
+		t.Fatalf("Expected FAIL for missing image, got %s", result.Status)
+	}
+	if len(result.Missing) != 1 || result.Missing[0].URL != "/img/missing.png" {
+		t.Errorf("Expected only /img/missing.png missing, got %v", result.Missing)
+	}
+	if result.Total != 5 {
+		t.Errorf("Expected 5 local assets, got %d", result.Total)
+	}
+}
+
+func TestCheckAssetsBasePath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head><link rel="stylesheet" href="/docs/style.css"></head>
+<body><img src="/hero.jpg"></body>
+</html>`
+
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(htmlContent), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "style.css"), []byte("body {}"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "hero.jpg"), []byte{}, 0644)
+
+	env := NewEnv(tmpDir)
+	env.Config.BasePath = "/docs"
+	result := CheckAssets(env)
+
+	// /hero.jpg escapes the base path, so it is missing once deployed
+	if len(result.Missing) != 1 || result.Missing[0].URL != "/hero.jpg" {
+		t.Errorf("Expected /hero.jpg missing under base path, got %v", result.Missing)
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	indexHTML := `<!DOCTYPE html>
+<html>
+<body>
+	<a href="/about/">About</a>
+	<a href="/contact">Contact</a>
+	<a href="/pricing/#plans">Pricing</a>
+	<a href="#top">Top</a>
+	<a href="#intro">Intro</a>
+	<a href="https://example.com/">External</a>
+	<a href="mailto:hi@example.com">Mail</a>
+	<a href="/missing/">Missing</a>
+	<a href="/pricing/#enterprise">Enterprise</a>
+	<h2 id="intro">Intro</h2>
+</body>
+</html>`
+	pricingHTML := `<!DOCTYPE html>
+<html>
+<body><section id="plans"></section><a name="faq"></a><a href="../#intro">Back</a></body>
+</html>`
+
+	os.MkdirAll(filepath.Join(tmpDir, "about"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "pricing"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(indexHTML), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "about", "index.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "contact.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "pricing", "index.html"), []byte(pricingHTML), 0644)
+
+	result := CheckLinks(NewEnv(tmpDir))
+	if result.Status != "FAIL" {
+		t.Fatalf("Expected FAIL for broken links, got %s", result.Status)
+	}
+	if result.Total != 8 {
+		t.Errorf("Expected 8 internal links, got %d", result.Total)
+	}
+	if len(result.Broken) != 2 {
+		t.Fatalf("Expected 2 broken links, got %+v", result.Broken)
+	}
+
+	missing := result.Broken[0]
+	if missing.URL != "/missing/" || missing.Page != "index.html" || missing.Line != 11 {
+		t.Errorf("Expected /missing/ at index.html:11, got %+v", missing)
+	}
+	if result.Broken[1].URL != "/pricing/#enterprise" {
+		t.Errorf("Expected broken fragment /pricing/#enterprise, got %+v", result.Broken[1])
+	}
+}
+
+func TestExtractCSSURLs(t *testing.T) {
+	css := `@import "base.css";
+@import url('theme/dark.css') screen;
+/* background: url(commented.png); */
+@font-face {
+	font-family: "Inter";
+	src: url("../fonts/inter.woff2") format("woff2"), url(../fonts/inter.woff) format("woff");
+}
+.hero { background-image: URL( hero.jpg ); }
+.icon { mask: url(data:image/svg+xml;base64,AAAA); }`
+
+	urls := extractCSSURLs(css)
+	expected := []string{
+		"base.css",
+		"theme/dark.css",
+		"../fonts/inter.woff2",
+		"../fonts/inter.woff",
+		"hero.jpg",
+		"data:image/svg+xml;base64,AAAA",
+	}
+	if len(urls) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, urls)
+	}
+	for i := range expected {
+		if urls[i].URL != expected[i] {
+			t.Errorf("Expected %q at %d, got %q", expected[i], i, urls[i].URL)
+		}
+	}
+}
+
+func TestExtractCSSURLsOffsets(t *testing.T) {
+	// Comments with multi-byte characters must not shift the offsets
+	css := "/* © 2024 — Ünï */ .a { background: url(missing.png); }\n/* ✓ */ @import \"b.css\";"
+	refs := extractCSSURLs(css)
+	if len(refs) != 2 {
+		t.Fatalf("Expected 2 references, got %v", refs)
+	}
+	for _, ref := range refs {
+		if want := map[string]string{"missing.png": "url(missing.png)", "b.css": `@import "b.css"`}[ref.URL]; !strings.HasPrefix(css[ref.Offset:], want) {
+			t.Errorf("Offset of %q points at %q, want %q", ref.URL, css[ref.Offset:], want)
+		}
+	}
+}
+
+func TestCheckAssetsCSS(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head>
+	<link rel="stylesheet" href="/css/style.css">
+	<style>.banner { background: url("/img/banner.jpg"); }</style>
+</head>
+<body><div style="background-image: url('img/inline.png')"></div></body>
+</html>`
+
+	os.MkdirAll(filepath.Join(tmpDir, "css", "vendor"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "fonts"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "img"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(htmlContent), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "css", "style.css"), []byte(`@import "vendor/reset.css";
+@font-face { src: url(../fonts/inter.woff2); }`), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "css", "vendor", "reset.css"), []byte(`body { background: url(../../img/noise.png); }`), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "img", "banner.jpg"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "img", "inline.png"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "img", "noise.png"), []byte{}, 0644)
+	// Note: fonts/inter.woff2 is missing
+
+	result := CheckAssets(NewEnv(tmpDir))
+	if result.Status != "FAIL" {
+		t.Fatalf("Expected FAIL for missing font, got %s", result.Status)
+	}
+	if len(result.Missing) != 1 || result.Missing[0].URL != "../fonts/inter.woff2" {
+		t.Errorf("Expected missing ../fonts/inter.woff2, got %v", result.Missing)
+	}
+	if result.Total != 6 {
+		t.Errorf("Expected 6 assets, got %d", result.Total)
+	}
+}
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		srcset   string
+		expected []string
+	}{
+		{"hero.jpg", []string{"hero.jpg"}},
+		{"hero-1x.jpg 1x, hero-2x.jpg 2x", []string{"hero-1x.jpg", "hero-2x.jpg"}},
+		{" small.jpg 480w ,large.jpg   1080w ", []string{"small.jpg", "large.jpg"}},
+		{"data:image/png;base64,iVBOR= 1x, full.png 2x", []string{"data:image/png;base64,iVBOR=", "full.png"}},
+		{"a.jpg,, b.jpg 2x", []string{"a.jpg", "b.jpg"}},
+		{"a.jpg (future descriptor, with comma) 1x, b.jpg 2x", []string{"a.jpg", "b.jpg"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := parseSrcset(tt.srcset)
+		if len(got) != len(tt.expected) {
+			t.Errorf("parseSrcset(%q) = %v, want %v", tt.srcset, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("parseSrcset(%q) = %v, want %v", tt.srcset, got, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestExtractAssetsFullCoverage(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head>
+	<link rel="shortcut icon" href="favicon.ico">
+	<link rel="apple-touch-icon" href="apple-touch-icon.png">
+	<link rel="manifest" href="site.webmanifest">
+	<link rel="preload" href="fonts/inter.woff2" as="font">
+	<link rel="modulepreload" href="app.mjs">
+	<link rel="preconnect" href="https://fonts.gstatic.com">
+	<link rel="canonical" href="https://example.com/">
+	<meta property="og:image" content="/og.png">
+	<meta name="twitter:image" content="/twitter.png">
+</head>
+<body>
+	<img src="hero.jpg" srcset="hero-2x.jpg 2x">
+	<video src="intro.mp4" poster="poster.jpg">
+		<source src="intro.webm" type="video/webm">
+		<track src="captions.vtt" kind="captions">
+	</video>
+	<audio src="theme.mp3"></audio>
+	<object data="diagram.svg"></object>
+	<embed src="widget.swf">
+	<input type="image" src="submit.png">
+	<input type="text" src="ignored.png">
+</body>
+</html>`
+
+	htmlFile := filepath.Join(tmpDir, "index.html")
+	os.WriteFile(htmlFile, []byte(htmlContent), 0644)
+
+	assets, err := extractAssets(htmlFile)
+	if err != nil {
+		t.Fatalf("extractAssets failed: %v", err)
+	}
+
+	expected := map[string]bool{
+		"favicon.ico": true, "apple-touch-icon.png": true, "site.webmanifest": true,
+		"fonts/inter.woff2": true, "app.mjs": true, "/og.png": true, "/twitter.png": true,
+		"hero.jpg": true, "hero-2x.jpg": true, "intro.mp4": true, "poster.jpg": true,
+		"intro.webm": true, "captions.vtt": true, "theme.mp3": true, "diagram.svg": true,
+		"widget.swf": true, "submit.png": true,
+	}
+	got := make(map[string]bool)
+	for _, a := range assets {
+		got[a.URL] = true
+		if !expected[a.URL] {
+			t.Errorf("Unexpected asset %q", a.URL)
+		}
+	}
+	for u := range expected {
+		if !got[u] {
+			t.Errorf("Expected asset %q", u)
+		}
+	}
+}
+
+func TestCheckAssetsFindings(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	pageHTML := `<!DOCTYPE html>
+<html>
+<head><link rel="stylesheet" href="/style.css"></head>
+<body>
+	<img src="/logo.png" srcset="/logo-2x.png 2x">
+</body>
+</html>`
+
+	os.MkdirAll(filepath.Join(tmpDir, "about"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte(pageHTML), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "about", "index.html"), []byte(pageHTML), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "style.css"), []byte("body {\n  background: url(bg.png);\n}"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "logo-2x.png"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "bg.png"), []byte{}, 0644)
+
+	result := CheckAssets(NewEnv(tmpDir))
+
+	// Both pages reference the same 3 assets, and style.css references bg.png once
+	if result.Total != 7 {
+		t.Errorf("Expected 7 references, got %d", result.Total)
+	}
+	if result.Unique != 4 {
+		t.Errorf("Expected 4 unique assets, got %d", result.Unique)
+	}
+	if len(result.Missing) != 2 {
+		t.Fatalf("Expected logo.png missing from both pages, got %+v", result.Missing)
+	}
+
+	f := result.Missing[0]
+	if f.Source != "about/index.html" || f.Line != 5 || f.Column != 2 {
+		t.Errorf("Expected about/index.html:5:2, got %s", f.Location())
+	}
+	if f.URL != "/logo.png" || f.Path != "logo.png" || f.Element != "img" || f.Attribute != "src" || f.Reason != "file not found" {
+		t.Errorf("Unexpected finding %+v", f)
+	}
+
+	os.Remove(filepath.Join(tmpDir, "bg.png"))
+	os.WriteFile(filepath.Join(tmpDir, "logo.png"), []byte{}, 0644)
+	result = CheckAssets(NewEnv(tmpDir))
+	if len(result.Missing) != 1 {
+		t.Fatalf("Expected bg.png missing once, got %+v", result.Missing)
+	}
+	if f := result.Missing[0]; f.Location() != "style.css:2:15" || f.Attribute != "url()" || f.Element != "" {
+		t.Errorf("Expected url() at style.css:2:15, got %+v", f)
+	}
+}
+
+func TestSitePages(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	os.MkdirAll(filepath.Join(tmpDir, "blog"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "about.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "404.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "blog", "index.html"), []byte("<html></html>"), 0644)
+
+	pages, err := NewEnv(tmpDir).SitePages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/", "/about.html", "/blog/"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("Expected pages %v from HTML files, got %v", want, pages)
+	}
+
+	// A sitemap, possibly an index of sitemaps, takes precedence
+	os.WriteFile(filepath.Join(tmpDir, "sitemap.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/docs/sitemap-0.xml</loc></sitemap>
+</sitemapindex>`), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "sitemap-0.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/docs/</loc></url>
+  <url><loc>https://example.com/docs/blog/first-post/</loc></url>
+  <url><loc>https://example.com/elsewhere/</loc></url>
+</urlset>`), 0644)
+
+	env := NewEnv(tmpDir)
+	env.Config.BasePath = "/docs/"
+	pages, err = env.SitePages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/", "/blog/first-post/"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("Expected pages %v from sitemap, got %v", want, pages)
+	}
+}
+
+func TestScreenshotPages(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "pricing"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "404.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "pricing", "index.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "sitemap.xml"), []byte(`<urlset><url><loc>https://example.com/</loc></url></urlset>`), 0644)
+
+	env := NewEnv(tmpDir)
+	env.Config.Pages = []string{"/", "/contact/"}
+	if pages, _ := env.ScreenshotPages(); !reflect.DeepEqual(pages, []string{"/", "/contact/"}) {
+		t.Errorf("Expected the configured pages, got %v", pages)
+	}
+	env.Config.Screenshots.Pages = []string{"/pricing/"}
+	if pages, _ := env.ScreenshotPages(); !reflect.DeepEqual(pages, []string{"/pricing/"}) {
+		t.Errorf("Expected screenshots.pages, got %v", pages)
+	}
+	// Every HTML file, whatever the sitemap lists
+	env.Config.Screenshots.AllPages = true
+	if pages, _ := env.ScreenshotPages(); !reflect.DeepEqual(pages, []string{"/", "/pricing/"}) {
+		t.Errorf("Expected every page, got %v", pages)
+	}
+
+	if got := ScreenshotFile(pageSlugs([]string{"/pricing/"})[0], "mobile"); got != "pricing.mobile.png" {
+		t.Errorf("ScreenshotFile() = %q", got)
+	}
+}
+
+func TestSamplePages(t *testing.T) {
+	candidates := []string{"/", "/a/", "/b/", "/c/", "/d/", "/e/", "/f/"}
+
+	got := samplePages([]string{"/"}, candidates, 4)
+	if want := []string{"/", "/a/", "/c/", "/e/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := samplePages([]string{"/", "/x/"}, candidates, 1); len(got) != 2 {
+		t.Errorf("Expected configured pages to be kept, got %v", got)
+	}
+	if got := samplePages(nil, candidates, 20); len(got) != len(candidates) {
+		t.Errorf("Expected every candidate, got %v", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	scores := []int{98, 61, 90, 75}
+	tests := []struct {
+		p    int
+		want int
+	}{
+		{0, 61},
+		{25, 61},
+		{50, 75},
+		{75, 90},
+		{100, 98},
+	}
+	for _, tt := range tests {
+		if got := percentile(scores, tt.p); got != tt.want {
+			t.Errorf("percentile(p%d) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestParseLighthouseJSON(t *testing.T) {
+	data := []byte(`{
+  "categories": {
+    "performance": {"score": 0.5},
+    "accessibility": {"score": 1},
+    "seo": {"score": 0.9},
+    "best-practices": {"score": 0.29}
+  },
+  "audits": {
+    "largest-contentful-paint": {"numericValue": 3120.5, "displayValue": "3.1 s"},
+    "cumulative-layout-shift": {"numericValue": 0.12},
+    "total-blocking-time": {"numericValue": 250},
+    "total-byte-weight": {"numericValue": 1650000},
+    "viewport": {"score": 1}
+  }
+}`)
+	scores, err := parseLighthouseJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0.29 * 100 is just under 29, which must not truncate to 28
+	if scores.Performance != 50 || scores.Accessibility != 100 || scores.SEO != 90 || scores.BestPractices != 29 || scores.PWA != 0 {
+		t.Errorf("Unexpected scores %+v", scores)
+	}
+	if scores.Metrics == nil {
+		t.Fatal("Expected metrics")
+	}
+	m := *scores.Metrics
+	if m.LCP != 3120.5 || m.CLS != 0.12 || m.TBT != 250 || m.TotalByteWeight != 1650000 || m.FCP != 0 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+}
+
+func TestCombineScores(t *testing.T) {
+	runs := []report.LighthouseScores{
+		{Performance: 98, Accessibility: 100, SEO: 100, Metrics: &report.LighthouseMetrics{LCP: 1200, CLS: 0.01}},
+		{Performance: 61, Accessibility: 90, SEO: 100, Metrics: &report.LighthouseMetrics{LCP: 4100, CLS: 0.2}},
+		{Performance: 75, Accessibility: 95, SEO: 100, Metrics: &report.LighthouseMetrics{LCP: 2600, CLS: 0.05}},
+	}
+
+	worst := combineScores(runs, 0)
+	if worst.Performance != 61 || worst.Accessibility != 90 {
+		t.Errorf("Expected lowest scores, got %+v", worst)
+	}
+	if worst.Metrics.LCP != 4100 || worst.Metrics.CLS != 0.2 {
+		t.Errorf("Expected highest metrics, got %+v", *worst.Metrics)
+	}
+
+	median := combineScores(runs, 50)
+	if median.Performance != 75 || median.Metrics.LCP != 2600 {
+		t.Errorf("Expected medians, got %+v %+v", median, *median.Metrics)
+	}
+}
+
+func TestParseLighthouseJSONAudits(t *testing.T) {
+	data := []byte(`{
+  "categories": {
+    "accessibility": {"score": 0.84, "auditRefs": [
+      {"id": "color-contrast", "weight": 7},
+      {"id": "image-alt", "weight": 10},
+      {"id": "html-has-lang", "weight": 7},
+      {"id": "tabindex", "weight": 7}
+    ]},
+    "performance": {"score": 0.7, "auditRefs": [
+      {"id": "render-blocking-resources", "weight": 0},
+      {"id": "diagnostics", "weight": 0}
+    ]}
+  },
+  "audits": {
+    "color-contrast": {"title": "Background and foreground colors do not have a sufficient contrast ratio.", "score": 0, "scoreDisplayMode": "binary",
+      "details": {"type": "table", "items": [
+        {"node": {"type": "node", "selector": ".hero > a.btn", "snippet": "<a class=\"btn\">"}},
+        {"node": {"type": "node", "selector": "footer p"}}
+      ]}},
+    "image-alt": {"title": "Image elements do not have [alt] attributes", "score": 0, "scoreDisplayMode": "binary",
+      "details": {"type": "table", "items": [{"node": {"type": "node", "selector": "img.logo"}}]}},
+    "html-has-lang": {"title": "<html> element has a [lang] attribute", "score": 1, "scoreDisplayMode": "binary"},
+    "tabindex": {"title": "No element has a [tabindex] value greater than 0", "score": null, "scoreDisplayMode": "notApplicable"},
+    "render-blocking-resources": {"title": "Eliminate render-blocking resources", "score": 0.5, "scoreDisplayMode": "metricSavings", "displayValue": "Potential savings of 450 ms",
+      "details": {"type": "opportunity", "items": [{"url": "http://localhost/style.css", "wastedMs": 450}]}},
+    "diagnostics": {"title": "Diagnostics", "score": null, "scoreDisplayMode": "informative"}
+  }
+}`)
+	run, err := parseLighthouseJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Accessibility != 84 || run.Performance != 70 {
+		t.Errorf("Unexpected scores %+v", run.LighthouseScores)
+	}
+
+	var ids []string
+	for _, a := range run.audits {
+		ids = append(ids, a.ID)
+	}
+	// Score 0 first, heavier audits first, then the partial score
+	if want := []string{"image-alt", "color-contrast", "render-blocking-resources"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("Expected audits %v, got %v", want, ids)
+	}
+	if items := run.audits[1].Items; !reflect.DeepEqual(items, []string{".hero > a.btn", "footer p"}) {
+		t.Errorf("Expected offending selectors, got %v", items)
+	}
+	rb := run.audits[2]
+	if rb.Category != "performance" || rb.DisplayValue != "Potential savings of 450 ms" || !reflect.DeepEqual(rb.Items, []string{"http://localhost/style.css"}) {
+		t.Errorf("Unexpected audit %+v", rb)
+	}
+
+	merged := mergeAudits([]report.LighthousePage{
+		{URL: "/", Audits: run.audits[:1]},
+		{URL: "/blog/", Audits: []report.LighthouseAudit{{ID: "image-alt", Score: 0, Items: []string{"img.cover"}}}},
+	})
+	if len(merged) != 1 || !reflect.DeepEqual(merged[0].Pages, []string{"/", "/blog/"}) || len(merged[0].Items) != 2 {
+		t.Errorf("Unexpected merged audits %+v", merged)
+	}
+}
+
+func TestPageSlugs(t *testing.T) {
+	pages := []string{"/", "/blog/first-post/", "/about.html", "/blog-first-post", "/search?q=a b"}
+	want := []string{"index", "blog-first-post", "about.html", "blog-first-post-2", "search_q_a_b"}
+	if got := pageSlugs(pages); !reflect.DeepEqual(got, want) {
+		t.Errorf("pageSlugs() = %v, want %v", got, want)
+	}
+}
+
+func TestLighthouseFlags(t *testing.T) {
+	cfg := config.Default().Lighthouse
+	cfg.Categories = []string{"performance", "seo"}
+	cfg.Desktop.Throttling = config.ThrottlingConfig{Method: "devtools", CPUSlowdown: 1.5}
+	cfg.Desktop.Screen = config.ScreenConfig{Width: 1440, Height: 900}
+
+	if got := lighthouseFlags("mobile", cfg); !reflect.DeepEqual(got, []string{"--only-categories=performance,seo"}) {
+		t.Errorf("mobile flags = %v", got)
+	}
+	want := []string{
+		"--only-categories=performance,seo",
+		"--preset=desktop",
+		"--throttling-method=devtools",
+		"--throttling.cpuSlowdownMultiplier=1.5",
+		"--screenEmulation.width=1440",
+		"--screenEmulation.height=900",
+	}
+	if got := lighthouseFlags("desktop", cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("desktop flags = %v, want %v", got, want)
+	}
+}
+
+func TestClassifyLighthouseError(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	tests := []struct {
+		name   string
+		err    error
+		output string
+		want   string
+	}{
+		{"npx missing", exec.ErrNotFound, "", report.LighthouseNotInstalled},
+		{"package missing", exitErr, "npm error could not determine executable to run\n", report.LighthouseNotInstalled},
+		{"no chrome", exitErr, "Runtime error encountered: No Chrome installations found.\n", report.LighthouseChromeLaunch},
+		{"connect", exitErr, "LH:ChromeLauncher Unable to connect to Chrome\n", report.LighthouseChromeLaunch},
+		{"navigation", exitErr, "Error: net::ERR_CONNECTION_REFUSED\n", report.LighthouseNavigation},
+		{"protocol timeout", exitErr, "LighthouseError: PROTOCOL_TIMEOUT\n", report.LighthouseTimeout},
+		{"other", exitErr, "TypeError: undefined is not a function\n", report.LighthouseRuntime},
+	}
+	for _, tt := range tests {
+		got := classifyLighthouseError(context.Background(), tt.err, []byte(tt.output), time.Minute)
+		if got.Kind != tt.want {
+			t.Errorf("%s: kind = %s, want %s (%v)", tt.name, got.Kind, tt.want, got)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	got := classifyLighthouseError(ctx, exitErr, []byte("Unable to connect to Chrome"), 90*time.Second)
+	if got.Kind != report.LighthouseTimeout || got.Error() != "lighthouse timed out after 1m30s" {
+		t.Errorf("Expected timeout, got %s: %v", got.Kind, got)
+	}
+
+	_, err := parseLighthouseJSON([]byte(`{"runtimeError": {"code": "NO_FCP", "message": "The page did not paint any content."}}`))
+	if kind := lighthouseErrorKind(err); kind != report.LighthouseNavigation {
+		t.Errorf("Expected runtimeError NO_FCP to be a navigation error, got %s: %v", kind, err)
+	}
+}
+
+func TestSetLighthouseError(t *testing.T) {
+	var r report.LighthouseResult
+	setLighthouseError(&r, &LighthouseError{report.LighthouseNotInstalled, errors.New("lighthouse not installed")})
+	if r.Status != report.StatusSkip || r.ErrorKind != report.LighthouseNotInstalled {
+		t.Errorf("Expected missing lighthouse to skip, got %s (%s)", r.Status, r.ErrorKind)
+	}
+	setLighthouseError(&r, &LighthouseError{report.LighthouseTimeout, errors.New("lighthouse timed out after 2m0s")})
+	if r.Status != report.StatusFail || r.ErrorKind != report.LighthouseTimeout || r.Summary() != "FAIL - lighthouse timed out after 2m0s" {
+		t.Errorf("Expected timeout to fail, got %s (%s): %s", r.Status, r.ErrorKind, r.Summary())
+	}
+}
+
+func TestLighthouseProbe(t *testing.T) {
+	ctx := context.Background()
+	if _, err := (Tools{}).lighthouseVersion(ctx, time.Second); lighthouseErrorKind(err) != report.LighthouseNotInstalled {
+		t.Errorf("Expected no lighthouse to be not installed, got %v", err)
+	}
+
+	// A probe that hangs, as npm does without a registry, is given up on
+	hang := filepath.Join(t.TempDir(), "lighthouse")
+	os.WriteFile(hang, []byte("#!/bin/sh\nsleep 10\n"), 0755)
+	start := time.Now()
+	_, err := Tools{Lighthouse: hang}.lighthouseVersion(ctx, 200*time.Millisecond)
+	if lighthouseErrorKind(err) != report.LighthouseNotInstalled || !strings.Contains(err.Error(), "did not answer within 200ms") {
+		t.Errorf("Expected a hung probe to be not installed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the probe to stop after its timeout, took %v", elapsed)
+	}
+
+	// Without Lighthouse the check stops before probing Node
+	dir := t.TempDir()
+	node := filepath.Join(dir, "node")
+	os.WriteFile(node, []byte("#!/bin/sh\ntouch \"$0.ran\"\n"), 0755)
+	result, err := CheckLighthouse(ctx, "http://localhost:1/", []string{"/"}, config.Default().Lighthouse, Tools{Node: node})
+	if lighthouseErrorKind(err) != report.LighthouseNotInstalled || result.Tools != nil {
+		t.Errorf("Expected not installed without tool versions, got %+v (%v)", result, err)
+	}
+	if _, err := os.Stat(node + ".ran"); err == nil {
+		t.Error("Expected Node not to be probed without Lighthouse")
+	}
+
+	timedOut, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	<-timedOut.Done()
+	if kind := probeError(timedOut, context.DeadlineExceeded, nil, 30*time.Second).Kind; kind != report.LighthouseNotInstalled {
+		t.Errorf("Expected a probe timeout to be not_installed, got %s", kind)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if kind := probeError(canceled, context.Canceled, nil, 30*time.Second).Kind; kind != report.LighthouseRuntime {
+		t.Errorf("Expected a canceled probe to stay a runtime error, got %s", kind)
+	}
+}
+
+// fakeLighthouseScript reports, for each run of a page, the next line of
+// the file named after the form factor and page next to it: a performance
+// score from 0 to 1, optionally followed by an LCP, or "fail".
+const fakeLighthouseScript = `#!/bin/sh
+if [ "$1" = --version ]; then echo 12.0.0; exit 0; fi
+ff=mobile html=
+for a; do
+	case "$a" in
+	--output-path=*) out=${a#--output-path=} ;;
+	--output=html) html=1 ;;
+	--preset=desktop) ff=desktop ;;
+	esac
+done
+page=$(echo "$1" | sed -e 's|^[a-z]*://[^/]*/||' -e 's|/$||' -e 's|/|-|g')
+runs="$(dirname "$0")/$ff/${page:-index}"
+set -- $(head -n 1 "$runs")
+tail -n +2 "$runs" > "$runs.tmp" && mv "$runs.tmp" "$runs"
+if [ "$1" = fail ]; then echo "Error: net::ERR_CONNECTION_REFUSED"; exit 1; fi
+if [ -n "$html" ]; then
+	echo "<html></html>" > "$out.report.html"
+	out="$out.report.json"
+fi
+audits=
+if [ -n "$2" ]; then audits="\"largest-contentful-paint\": {\"numericValue\": $2}"; fi
+echo "{\"categories\": {\"performance\": {\"score\": $1}, \"accessibility\": {\"score\": 1}, \"seo\": {\"score\": 1}, \"best-practices\": {\"score\": 1}}, \"audits\": {$audits}}" > "$out"
+`
+
+// fakeLighthouse returns tools that run fakeLighthouseScript with runs,
+// which maps "<form factor>/<page>" to the results of its runs, one per
+// line. Pages are named by their path without slashes, "index" for "/".
+// The tools name a Chrome port so that no Chrome is launched.
+func fakeLighthouse(t *testing.T, runs map[string]string) Tools {
+	t.Helper()
+	dir := t.TempDir()
+	for name, lines := range runs {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(lines), 0644)
+	}
+	lighthouse := filepath.Join(dir, "lighthouse")
+	os.WriteFile(lighthouse, []byte(fakeLighthouseScript), 0755)
+	return Tools{Lighthouse: lighthouse, ChromePort: 9222}
+}
+
+func TestCheckLighthousePages(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().Lighthouse
+	pages := []string{"/", "/blog/", "/about/"}
+
+	// A page that could not be audited fails the check but is left out of
+	// the aggregate scores
+	tools := fakeLighthouse(t, map[string]string{
+		"mobile/index": "0.95\n",
+		"mobile/blog":  "fail\n",
+		"mobile/about": "0.92\n",
+	})
+	result, err := CheckLighthouse(ctx, "http://localhost:1/", pages, cfg, tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != report.StatusFail || result.Performance != 92 {
+		t.Errorf("Expected FAIL with the lowest audited score, got %s %d: %s", result.Status, result.Performance, result.Details)
+	}
+	if !strings.Contains(result.Details, "could not audit /blog/") {
+		t.Errorf("Expected the failed page in details, got %q", result.Details)
+	}
+	if page := result.Pages[1]; page.ErrorKind != report.LighthouseNavigation || page.Performance != 0 {
+		t.Errorf("Expected /blog/ to fail to load, got %+v", page)
+	}
+
+	// The aggregate gates the thresholds: the minimum fails, the median passes
+	runs := map[string]string{
+		"mobile/index": "0.95\n",
+		"mobile/blog":  "0.6\n",
+		"mobile/about": "0.92\n",
+	}
+	result, err = CheckLighthouse(ctx, "http://localhost:1/", pages, cfg, fakeLighthouse(t, runs))
+	if err != nil || result.Status != report.StatusFail || result.Performance != 60 {
+		t.Errorf("Expected min to FAIL at 60, got %s %d (%v)", result.Status, result.Performance, err)
+	}
+	cfg.Aggregate = "median"
+	result, err = CheckLighthouse(ctx, "http://localhost:1/", pages, cfg, fakeLighthouse(t, runs))
+	if err != nil || result.Status != report.StatusPass || result.Performance != 92 {
+		t.Errorf("Expected median to PASS at 92, got %s %d (%v)", result.Status, result.Performance, err)
+	}
+	if !strings.HasSuffix(result.Details, "(median of 3 pages)") {
+		t.Errorf("Expected the aggregate in details, got %q", result.Details)
+	}
+
+	// Without any audited page the check fails with the page's error
+	tools = fakeLighthouse(t, map[string]string{"mobile/index": "fail\n"})
+	if _, err := CheckLighthouse(ctx, "http://localhost:1/", []string{"/"}, cfg, tools); lighthouseErrorKind(err) != report.LighthouseNavigation {
+		t.Errorf("Expected a navigation error, got %v", err)
+	}
+}
+
+func TestCheckLighthouseRuns(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().Lighthouse
+	cfg.Runs = 5
+	cfg.MaxSpread = 20
+	// The failed third run is left out of the median and the spread
+	runs := map[string]string{"mobile/index": "0.9 1000\n0.7 3000\nfail\n0.95 900\n0.8 2000\n"}
+
+	result, err := CheckLighthouse(ctx, "http://localhost:1/", []string{"/"}, cfg, fakeLighthouse(t, runs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := result.Pages[0]
+	if len(page.Runs) != 4 || page.Performance != 80 || page.Metrics == nil || page.Metrics.LCP != 1000 {
+		t.Errorf("Expected the lower medians of 4 runs, got %+v", page)
+	}
+	if page.Spread == nil || page.Spread.Performance != 25 || page.Spread.Accessibility != 0 {
+		t.Errorf("Expected a performance spread of 25, got %+v", page.Spread)
+	}
+	if !page.Unstable || !result.Unstable || !strings.Contains(result.Details, "scores varied by more than 20 points") {
+		t.Errorf("Expected the page to be unstable, got %+v: %s", page, result.Details)
+	}
+
+	cfg.MaxSpread = 25
+	result, err = CheckLighthouse(ctx, "http://localhost:1/", []string{"/"}, cfg, fakeLighthouse(t, runs))
+	if err != nil || result.Unstable || result.Pages[0].Unstable {
+		t.Errorf("Expected a spread within max_spread to be stable, got %+v (%v)", result, err)
+	}
+}
+
+func TestCheckLighthouseBudgets(t *testing.T) {
+	cfg := config.Default().Lighthouse
+	cfg.Budgets = config.LighthouseBudgets{LCP: config.Duration(2500 * time.Millisecond), CLS: 0.1}
+	// Only the home page reports an LCP, and no page a CLS
+	tools := fakeLighthouse(t, map[string]string{
+		"mobile/index": "0.95 1800\n",
+		"mobile/blog":  "0.95\n",
+	})
+	result, err := CheckLighthouse(context.Background(), "http://localhost:1/", []string{"/", "/blog/"}, cfg, tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []report.MetricBudget{
+		{Metric: "lcp", Budget: 2500, Value: 1800, Pass: true},
+		{Metric: "cls", Budget: 0.1, Missing: true},
+	}
+	if !reflect.DeepEqual(result.Budgets, want) {
+		t.Errorf("Budgets = %+v, want %+v", result.Budgets, want)
+	}
+	if result.Status != report.StatusFail || !strings.Contains(result.Summary(), "CLS not measured") {
+		t.Errorf("Expected an unmeasured budget to FAIL, got %s", result.Summary())
+	}
+}
+
+func TestCheckLighthouseArtifacts(t *testing.T) {
+	cfg := config.Default().Lighthouse
+	cfg.Runs = 2
+	cfg.FormFactors = []string{"mobile", "desktop"}
+	cfg.Artifacts = filepath.Join(t.TempDir(), "lighthouse")
+	tools := fakeLighthouse(t, map[string]string{
+		"mobile/index":  "0.95\n0.93\n",
+		"mobile/blog":   "fail\n0.91\n",
+		"desktop/index": "0.99\n0.98\n",
+		"desktop/blog":  "0.97\n0.96\n",
+	})
+	result, err := CheckLighthouse(context.Background(), "http://localhost:1/", []string{"/", "/blog/"}, cfg, tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.FormFactors) != 2 {
+		t.Fatalf("Expected a result per form factor, got %+v", result.FormFactors)
+	}
+
+	for _, r := range result.FormFactors {
+		dir := filepath.Join(cfg.Artifacts, r.FormFactor)
+		if r.Artifacts != dir {
+			t.Errorf("%s: Artifacts = %q, want %q", r.FormFactor, r.Artifacts, dir)
+		}
+		for _, page := range r.Pages {
+			slug := strings.Trim(page.URL, "/")
+			if slug == "" {
+				slug = "index"
+			}
+			// The failed first run of the mobile blog keeps no reports
+			runs := []int{1, 2}
+			if r.FormFactor == "mobile" && page.URL == "/blog/" {
+				runs = []int{2}
+			}
+			var want []report.LighthouseReport
+			for _, n := range runs {
+				base := filepath.Join(dir, fmt.Sprintf("%s.run-%d", slug, n))
+				want = append(want, report.LighthouseReport{Run: n, JSON: base + ".report.json", HTML: base + ".report.html"})
+			}
+			if !reflect.DeepEqual(page.Reports, want) {
+				t.Errorf("%s %s: Reports = %+v, want %+v", r.FormFactor, page.URL, page.Reports, want)
+			}
+			for _, rep := range page.Reports {
+				if _, err := os.Stat(rep.JSON); err != nil {
+					t.Error(err)
+				}
+				if _, err := os.Stat(rep.HTML); err != nil {
+					t.Error(err)
+				}
+			}
+		}
+	}
+}
+
+func TestCheckLighthouseFormFactors(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().Lighthouse
+	cfg.FormFactors = []string{"mobile", "desktop"}
+	cfg.Artifacts = filepath.Join(t.TempDir(), "lighthouse")
+
+	tools := fakeLighthouse(t, map[string]string{"mobile/index": "0.95\n", "desktop/index": "0.99\n"})
+	result, err := CheckLighthouse(ctx, "http://localhost:1/", []string{"/"}, cfg, tools)
+	if err != nil || result.Status != report.StatusPass || len(result.FormFactors) != 2 {
+		t.Fatalf("Expected both form factors to PASS, got %+v (%v)", result, err)
+	}
+	if m, d := result.FormFactors[0], result.FormFactors[1]; m.FormFactor != "mobile" || m.Performance != 95 || d.FormFactor != "desktop" || d.Performance != 99 {
+		t.Errorf("Unexpected form factor results %+v", result.FormFactors)
+	}
+	for _, ff := range cfg.FormFactors {
+		if info, err := os.Stat(filepath.Join(cfg.Artifacts, ff)); err != nil || !info.IsDir() {
+			t.Errorf("Expected an artifacts directory for %s: %v", ff, err)
+		}
+	}
+
+	// A form factor that could not be audited fails the check on its own
+	tools = fakeLighthouse(t, map[string]string{"mobile/index": "0.95\n", "desktop/index": "fail\n"})
+	result, err = CheckLighthouse(ctx, "http://localhost:1/", []string{"/"}, cfg, tools)
+	if err != nil || result.Status != report.StatusFail {
+		t.Fatalf("Expected FAIL without an error, got %s (%v)", result.Status, err)
+	}
+	if m := result.FormFactors[0]; m.Status != report.StatusPass {
+		t.Errorf("Expected mobile to PASS, got %s", m.Summary())
+	}
+	if d := result.FormFactors[1]; d.Status != report.StatusFail || d.ErrorKind != report.LighthouseNavigation {
+		t.Errorf("Expected desktop to fail to load, got %s (%s)", d.Summary(), d.ErrorKind)
+	}
+
+	tools = fakeLighthouse(t, map[string]string{"mobile/index": "fail\n", "desktop/index": "fail\n"})
+	if _, err := CheckLighthouse(ctx, "http://localhost:1/", []string{"/"}, cfg, tools); lighthouseErrorKind(err) != report.LighthouseNavigation {
+		t.Errorf("Expected an error when every form factor failed, got %v", err)
+	}
+}
+
+func TestResolveTools(t *testing.T) {
+	dir := t.TempDir()
+	chrome := filepath.Join(dir, "chrome")
+	os.WriteFile(chrome, []byte("#!/bin/sh\necho 'Chromium 126.0'\n"), 0755)
+	t.Setenv("PATH", dir)
+	t.Setenv(EnvLighthouse, "")
+	t.Setenv(EnvNode, "")
+	t.Setenv(EnvChrome, "")
+
+	tools := ResolveTools(config.ToolsConfig{Lighthouse: "/opt/lighthouse/cli/index.js"})
+	if tools.Lighthouse != "/opt/lighthouse/cli/index.js" || tools.Chrome != chrome {
+		t.Errorf("Unexpected tools %+v", tools)
+	}
+
+	// Without one on PATH, Lighthouse is found in node_modules of a parent
+	bin := filepath.Join(dir, "node_modules", ".bin")
+	os.MkdirAll(bin, 0755)
+	os.WriteFile(filepath.Join(bin, "lighthouse"), []byte("#!/bin/sh\n"), 0755)
+	os.MkdirAll(filepath.Join(dir, "site", "dist"), 0755)
+	t.Chdir(filepath.Join(dir, "site", "dist"))
+	if tools = ResolveTools(config.ToolsConfig{}); tools.Lighthouse != filepath.Join(bin, "lighthouse") {
+		t.Errorf("Expected lighthouse from node_modules, got %q", tools.Lighthouse)
+	}
+
+	t.Setenv(EnvLighthouse, "/usr/lib/lighthouse")
+	t.Setenv(EnvChrome, "/usr/bin/chromium")
+	tools = ResolveTools(config.ToolsConfig{Lighthouse: "/opt/lighthouse/cli/index.js", Chrome: "/opt/chrome"})
+	if tools.Lighthouse != "/usr/lib/lighthouse" || tools.Chrome != "/usr/bin/chromium" {
+		t.Errorf("Expected environment to override config, got %+v", tools)
+	}
+}
+
+func TestLighthouseCommand(t *testing.T) {
+	ctx := context.Background()
+	cmd := Tools{Lighthouse: "/usr/bin/lighthouse"}.lighthouseCommand(ctx, "--version")
+	if want := []string{"/usr/bin/lighthouse", "--version"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("lighthouse args = %v, want %v", cmd.Args, want)
+	}
+
+	tools := Tools{Lighthouse: "/opt/lighthouse/cli/index.js", Node: "/opt/node/bin/node", Chrome: "/opt/chrome"}
+	cmd = tools.lighthouseCommand(ctx, "--version")
+	if want := []string{"/opt/node/bin/node", "/opt/lighthouse/cli/index.js", "--version"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("node args = %v, want %v", cmd.Args, want)
+	}
+	env := strings.Join(cmd.Env, "\n")
+	if !strings.Contains(env, "\nPATH=/opt/node/bin"+string(os.PathListSeparator)) {
+		t.Errorf("Expected node on PATH in environment, got %v", cmd.Env)
+	}
+}
+
+func TestDevToolsPort(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := devToolsPort(dir); err == nil {
+		t.Error("Expected an error without DevToolsActivePort")
+	}
+	os.WriteFile(filepath.Join(dir, "DevToolsActivePort"), []byte("45123\n/devtools/browser/0b6f\n"), 0644)
+	if port, err := devToolsPort(dir); err != nil || port != 45123 {
+		t.Errorf("devToolsPort() = %d, %v", port, err)
+	}
+}
+
+func TestCaptureTimeout(t *testing.T) {
+	// The maximum wait leaves the margin to load and capture the page
+	for _, maxWait := range []time.Duration{10 * time.Second, 45 * time.Second} {
+		if got := captureTimeout(config.WaitConfig{MaxWait: config.Duration(maxWait)}); got != maxWait+captureMargin {
+			t.Errorf("captureTimeout(%v) = %v, want %v", maxWait, got, maxWait+captureMargin)
+		}
+	}
+}
+
+func TestReadinessConditions(t *testing.T) {
+	r := newReadiness(config.WaitConfig{
+		Conditions: []string{"fonts", "network_idle"},
+		Predicate:  "window.appReady",
+		Selector:   "#app",
+	})
+	var names []string
+	for _, c := range r.conditions() {
+		names = append(names, c.name)
+	}
+	if want := []string{"fonts", "network_idle", "selector", "predicate"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("conditions() = %v, want %v", names, want)
+	}
+
+	// The network idle condition waits for the tab's lifecycle event
+	idle := r.conditions()[1]
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := idle.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected network_idle to time out, got %v", err)
+	}
+	close(r.idle)
+	if err := idle.wait(context.Background()); err != nil {
+		t.Errorf("Expected network_idle to be met, got %v", err)
+	}
+}
+
+// testImage returns a white w x h image with the given pixels set.
+func testImage(w, h int, set map[image.Point]color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+	for p, c := range set {
+		img.SetNRGBA(p.X, p.Y, c)
+	}
+	return img
+}
+
+func TestDiffImages(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 255}
+	faint := color.NRGBA{250, 250, 250, 255}
+	block := make(map[image.Point]color.NRGBA)
+	for x := 2; x < 6; x++ {
+		for y := 2; y < 6; y++ {
+			block[image.Pt(x, y)] = black
+		}
+	}
+
+	// A thick line with a soft edge, which renderers anti-alias differently
+	line := func(edge uint8) map[image.Point]color.NRGBA {
+		set := make(map[image.Point]color.NRGBA)
+		for y := 0; y < 10; y++ {
+			set[image.Pt(3, y)], set[image.Pt(4, y)], set[image.Pt(5, y)] = black, black, black
+			set[image.Pt(6, y)] = color.NRGBA{edge, edge, edge, 255}
+		}
+		return set
+	}
+
+	tests := []struct {
+		name       string
+		a, b       image.Image
+		mismatched int
+	}{
+		{"identical", testImage(10, 10, block), testImage(10, 10, block), 0},
+		{"below threshold", testImage(10, 10, nil), testImage(10, 10, map[image.Point]color.NRGBA{{4, 4}: faint}), 0},
+		{"block added", testImage(10, 10, nil), testImage(10, 10, block), 16},
+		{"taller", testImage(10, 10, nil), testImage(10, 12, nil), 20},
+		{"anti-aliased edge", testImage(10, 10, line(128)), testImage(10, 10, line(90)), 0},
+		// The soft edge of a removed line still passes for anti-aliasing
+		{"line removed", testImage(10, 10, line(128)), testImage(10, 10, nil), 30},
+	}
+	for _, tt := range tests {
+		d := diffImages(tt.a, tt.b, 0.1)
+		if d.Mismatched != tt.mismatched {
+			t.Errorf("%s: %d pixels differ, want %d", tt.name, d.Mismatched, tt.mismatched)
+		}
+		if d.Image.Rect != tt.a.Bounds().Union(tt.b.Bounds()) {
+			t.Errorf("%s: diff image is %v", tt.name, d.Image.Rect)
+		}
+	}
+
+	d := diffImages(testImage(10, 10, nil), testImage(10, 10, block), 0.1)
+	if got := d.Image.NRGBAAt(3, 3); got.R != 255 || got.B != 0 {
+		t.Errorf("Expected a changed pixel to be highlighted, got %v", got)
+	}
+	if got := d.Image.NRGBAAt(0, 0); got.R != got.G || got.G != got.B {
+		t.Errorf("Expected an unchanged pixel to be gray, got %v", got)
+	}
+	if r := d.Ratio(); r != 0.16 {
+		t.Errorf("Ratio() = %v, want 0.16", r)
+	}
+}
+
+func TestAntialiased(t *testing.T) {
+	// A gray pixel between a flat black area and a flat white one, as on
+	// the edge of a glyph
+	set := make(map[image.Point]color.NRGBA)
+	for y := 0; y < 5; y++ {
+		set[image.Pt(0, y)], set[image.Pt(1, y)] = color.NRGBA{0, 0, 0, 255}, color.NRGBA{0, 0, 0, 255}
+	}
+	set[image.Pt(2, 2)] = color.NRGBA{128, 128, 128, 255}
+	img := testImage(5, 5, set)
+	if !antialiased(img, img, 2, 2, 5, 5) {
+		t.Error("Expected the edge pixel to look anti-aliased")
+	}
+	if antialiased(img, img, 4, 2, 5, 5) {
+		t.Error("Expected a pixel in a flat area not to look anti-aliased")
+	}
+}
+
+func TestCompareScreenshots(t *testing.T) {
+	dir := t.TempDir()
+	shots, baseline := filepath.Join(dir, "screenshots"), filepath.Join(dir, "baseline")
+	os.MkdirAll(shots, 0755)
+	os.MkdirAll(baseline, 0755)
+
+	block := make(map[image.Point]color.NRGBA)
+	for x := 0; x < 5; x++ {
+		block[image.Pt(x, 0)] = color.NRGBA{255, 0, 0, 255}
+	}
+	write := func(path string, img image.Image) {
+		if err := writePNG(path, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(shots, "index.desktop.png"), testImage(10, 10, nil))
+	write(filepath.Join(shots, "index.mobile.png"), testImage(10, 10, block))
+	write(filepath.Join(shots, "pricing.desktop.png"), testImage(10, 10, nil))
+	// The home page baseline is named after the viewport alone
+	write(filepath.Join(baseline, "desktop.png"), testImage(10, 10, nil))
+	write(filepath.Join(baseline, "index.mobile.png"), testImage(10, 10, nil))
+
+	result := CompareScreenshots(report.ScreenshotsResult{Pages: []report.ScreenshotPage{
+		{URL: "/", Viewports: map[string]string{
+			"mobile":  filepath.Join(shots, "index.mobile.png"),
+			"desktop": filepath.Join(shots, "index.desktop.png"),
+		}},
+		{URL: "/pricing/", Viewports: map[string]string{"desktop": filepath.Join(shots, "pricing.desktop.png")}},
+	}}, baseline, config.VisualConfig{Threshold: 0.1, MaxDiffRatio: 0.01, DiffDir: filepath.Join(shots, "diff")})
+
+	if result.Status != report.StatusFail || len(result.Captures) != 3 {
+		t.Fatalf("Expected the mobile capture to fail, got %+v", result)
+	}
+	desktop, mobile, pricing := result.Captures[0], result.Captures[1], result.Captures[2]
+	if desktop.Viewport != "desktop" || desktop.Baseline != filepath.Join(baseline, "desktop.png") || desktop.DiffPixels != 0 || desktop.Diff != "" {
+		t.Errorf("Unexpected desktop comparison %+v", desktop)
+	}
+	if mobile.DiffPixels != 5 || mobile.DiffRatio != 0.05 || !fileExists(mobile.Diff) {
+		t.Errorf("Unexpected mobile comparison %+v", mobile)
+	}
+	if pricing.Baseline != "" || pricing.Failed(result.MaxDiffRatio) {
+		t.Errorf("Expected no baseline for /pricing/, got %+v", pricing)
+	}
+
+	result = CompareScreenshots(report.ScreenshotsResult{}, baseline, config.VisualConfig{DiffDir: filepath.Join(shots, "diff")})
+	if result.Status != report.StatusSkip {
+		t.Errorf("Expected SKIP without captures, got %+v", result)
+	}
+}
@@ -0,0 +1,63 @@
+package checks
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	cssCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssURLRe     = regexp.MustCompile(`(?i)\burl\(\s*(?:"([^"]*)"|'([^']*)'|([^)"'\s]*))\s*\)`)
+	cssImportRe  = regexp.MustCompile(`(?i)@import\s+(?:"([^"]*)"|'([^']*)')`)
+)
+
+// cssRef is a URL referenced by CSS.
+type cssRef struct {
+	URL string
+	// Syntax is "url()" or "@import".
+	Syntax string
+	// Offset is the byte offset of the reference in the CSS text.
+	Offset int
+}
+
+// extractCSSURLs returns the URLs referenced by a stylesheet or style
+// attribute in source order: every url() value (backgrounds, @font-face
+// sources, imports written as url()) and every @import given as a plain
+// string.
+func extractCSSURLs(css string) []cssRef {
+	// Blank out comments byte by byte, keeping offsets and line breaks
+	// intact even around multi-byte characters
+	css = cssCommentRe.ReplaceAllStringFunc(css, func(comment string) string {
+		b := []byte(comment)
+		for i := range b {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+		return string(b)
+	})
+
+	var refs []cssRef
+	for _, syntax := range []struct {
+		re   *regexp.Regexp
+		name string
+	}{
+		{cssImportRe, "@import"},
+		{cssURLRe, "url()"},
+	} {
+		for _, m := range syntax.re.FindAllStringSubmatchIndex(css, -1) {
+			for g := 1; g < len(m)/2; g++ {
+				if m[2*g] < 0 {
+					continue
+				}
+				if v := strings.TrimSpace(css[m[2*g]:m[2*g+1]]); v != "" {
+					refs = append(refs, cssRef{URL: v, Syntax: syntax.name, Offset: m[0]})
+					break
+				}
+			}
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].Offset < refs[j].Offset })
+	return refs
+}
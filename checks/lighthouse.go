@@ -0,0 +1,480 @@
+package checks
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+type lighthouseCheck struct{}
+
+func (lighthouseCheck) Name() string           { return "lighthouse" }
+func (lighthouseCheck) Dependencies() []string { return nil }
+func (lighthouseCheck) Severity() Severity     { return SeverityError }
+
+func (lighthouseCheck) Run(ctx context.Context, env *Env) report.Result {
+	srv, err := env.Server()
+	if err != nil {
+		return report.LighthouseResult{Status: report.StatusFail, Details: err.Error()}
+	}
+	pages, err := lighthousePages(env)
+	if err != nil {
+		return report.LighthouseResult{Status: report.StatusFail, Details: fmt.Sprintf("Error listing pages: %v", err)}
+	}
+	tools := env.Tools()
+	if env.Config.Lighthouse.SharedBrowser {
+		b, err := env.Browser()
+		if err != nil {
+			var result report.LighthouseResult
+			setLighthouseError(&result, &LighthouseError{report.LighthouseChromeLaunch, err})
+			return result
+		}
+		tools.ChromePort = b.Port
+	}
+	result, err := CheckLighthouse(ctx, srv.URL(), pages, env.Config.Lighthouse, tools)
+	if err != nil {
+		setLighthouseError(&result, err)
+	}
+	return result
+}
+
+// setLighthouseError records err, which stopped the audits of r, in r. A
+// missing Lighthouse skips the check; any other error fails it.
+func setLighthouseError(r *report.LighthouseResult, err error) {
+	r.ErrorKind = lighthouseErrorKind(err)
+	r.Status = report.StatusFail
+	if r.ErrorKind == report.LighthouseNotInstalled {
+		r.Status = report.StatusSkip
+	}
+	r.Details = err.Error()
+}
+
+// lighthousePages returns the pages to audit: the configured pages, plus a
+// sample of the site's pages if lighthouse.sample is set.
+func lighthousePages(env *Env) ([]string, error) {
+	pages := env.Config.Pages
+	if len(pages) == 0 {
+		pages = []string{"/"}
+	}
+	if env.Config.Lighthouse.Sample <= 0 {
+		return pages, nil
+	}
+	candidates, err := env.SitePages()
+	if err != nil {
+		return nil, err
+	}
+	return samplePages(pages, candidates, env.Config.Lighthouse.Sample), nil
+}
+
+// CheckLighthouse runs Lighthouse audits of pages, paths relative to the
+// site served at siteURL, for each form factor of cfg, and gates the
+// aggregate scores on the thresholds of each form factor. With a single
+// form factor its result is returned as is; otherwise the result holds one
+// per form factor. It returns a *LighthouseError if no page could be
+// audited. Every Lighthouse run is stopped when ctx is done or after
+// cfg.Timeout. The versions of the tools are recorded in the result.
+func CheckLighthouse(ctx context.Context, siteURL string, pages []string, cfg config.LighthouseConfig, tools Tools) (report.LighthouseResult, error) {
+	// Check if lighthouse is available before probing what it runs on
+	probe := time.Duration(cfg.ProbeTimeout)
+	version, err := tools.lighthouseVersion(ctx, probe)
+	if err != nil {
+		return report.LighthouseResult{Status: "PASS"}, err
+	}
+	versions := tools.runtimeVersions(ctx, probe)
+	versions.Lighthouse = version
+
+	formFactors := cfg.FormFactors
+	if len(formFactors) == 0 {
+		formFactors = []string{"mobile"}
+	}
+	if len(formFactors) == 1 {
+		result, err := auditFormFactor(ctx, siteURL, pages, formFactors[0], cfg.Artifacts, cfg, tools)
+		result.Tools = &versions
+		return result, err
+	}
+
+	result := report.LighthouseResult{Status: "PASS", Tools: &versions}
+	var lastErr error
+	errs := 0
+	for _, ff := range formFactors {
+		artifacts := cfg.Artifacts
+		if artifacts != "" {
+			artifacts = filepath.Join(artifacts, ff)
+		}
+		r, err := auditFormFactor(ctx, siteURL, pages, ff, artifacts, cfg, tools)
+		if err != nil {
+			lastErr = err
+			errs++
+			setLighthouseError(&r, err)
+		}
+		if r.Status == "FAIL" {
+			result.Status = "FAIL"
+		}
+		result.FormFactors = append(result.FormFactors, r)
+	}
+	if errs == len(formFactors) {
+		return result, lastErr
+	}
+	return result, nil
+}
+
+// auditFormFactor audits pages for the named form factor, keeping the
+// full reports in artifacts if set.
+func auditFormFactor(ctx context.Context, siteURL string, pages []string, formFactor, artifacts string, cfg config.LighthouseConfig, tools Tools) (report.LighthouseResult, error) {
+	t := cfg.ThresholdsFor(formFactor)
+	result := report.LighthouseResult{
+		Status:     "PASS",
+		FormFactor: formFactor,
+		Categories: cfg.Categories,
+		Aggregate:  cfg.Aggregate,
+		Thresholds: report.Thresholds{
+			Performance:   t.Performance,
+			Accessibility: t.Accessibility,
+			SEO:           t.SEO,
+			BestPractices: t.BestPractices,
+			PWA:           t.PWA,
+		},
+	}
+	flags := lighthouseFlags(formFactor, cfg)
+
+	var slugs []string
+	if artifacts != "" {
+		if err := os.MkdirAll(artifacts, 0755); err != nil {
+			result.Status = "FAIL"
+			result.Details = fmt.Sprintf("Failed to create artifacts directory: %v", err)
+			return result, nil
+		}
+		result.Artifacts = artifacts
+		slugs = pageSlugs(pages)
+	}
+
+	// Audit the pages with a bounded number of workers
+	result.Pages = make([]report.LighthousePage, len(pages))
+	workers := max(1, min(cfg.Concurrency, len(pages)))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				var reportPath string
+				if slugs != nil {
+					reportPath = filepath.Join(artifacts, slugs[i])
+				}
+				result.Pages[i] = auditPage(ctx, tools, siteURL, pages[i], reportPath, flags, cfg)
+			}
+		}()
+	}
+	for i := range pages {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var audited []report.LighthouseScores
+	var failed []string
+	for _, page := range result.Pages {
+		if page.Error != "" {
+			failed = append(failed, page.URL)
+			continue
+		}
+		if page.Unstable {
+			result.Unstable = true
+		}
+		audited = append(audited, page.LighthouseScores)
+	}
+	if len(failed) == len(pages) {
+		first := result.Pages[0]
+		return result, &LighthouseError{first.ErrorKind, fmt.Errorf("lighthouse failed: %s", first.Error)}
+	}
+
+	p, _ := cfg.Percentile()
+	result.LighthouseScores = combineScores(audited, p)
+	result.Audits = mergeAudits(result.Pages)
+
+	// Check budgets; a metric no run measured cannot be within its budget
+	limits := cfg.Budgets.Limits()
+	for _, m := range report.Metrics {
+		limit, ok := limits[m.Key]
+		if !ok {
+			continue
+		}
+		budget := report.MetricBudget{Metric: m.Key, Budget: limit, Missing: !result.Metrics.Has(m.Key)}
+		if !budget.Missing {
+			budget.Value = *m.Field(result.Metrics)
+			budget.Pass = budget.Value <= limit
+		}
+		if !budget.Pass {
+			result.Status = "FAIL"
+		}
+		result.Budgets = append(result.Budgets, budget)
+	}
+
+	// Check thresholds
+	var scores []string
+	for _, id := range cfg.Categories {
+		c, ok := report.LookupCategory(id)
+		if !ok {
+			continue
+		}
+		score := *c.Score(&result.LighthouseScores)
+		if score < *c.Threshold(&result.Thresholds) {
+			result.Status = "FAIL"
+		}
+		scores = append(scores, fmt.Sprintf("%s: %d", c.Label, score))
+	}
+
+	result.Details = strings.Join(scores, ", ")
+	if len(pages) > 1 {
+		result.Details += fmt.Sprintf(" (%s of %d pages)", cfg.Aggregate, len(pages))
+	}
+	if len(failed) > 0 {
+		result.Status = "FAIL"
+		result.Details += fmt.Sprintf("; could not audit %s", strings.Join(failed, ", "))
+	}
+	if result.Unstable {
+		result.Details += fmt.Sprintf("; scores varied by more than %d points between runs", cfg.MaxSpread)
+	}
+
+	return result, nil
+}
+
+// auditPage runs cfg.Runs Lighthouse audits of the page at path and keeps
+// the median score of each category and the median of each metric, and the
+// failing audits of the run with the median performance score. Failed runs
+// are left out; the page has an error only if every run failed. If
+// artifacts is set, the reports of run N are kept as
+// artifacts.run-N.report.json and .html. flags are passed to every run.
+// Runs stop when ctx is done.
+func auditPage(ctx context.Context, tools Tools, siteURL, path, artifacts string, flags []string, cfg config.LighthouseConfig) report.LighthousePage {
+	page := report.LighthousePage{URL: path}
+	runs := max(cfg.Runs, 1)
+
+	var lastErr error
+	var audits [][]report.LighthouseAudit
+	for i := 0; i < runs && ctx.Err() == nil; i++ {
+		var output string
+		if artifacts != "" {
+			output = fmt.Sprintf("%s.run-%d", artifacts, i+1)
+		}
+		run, err := runLighthouse(ctx, tools, siteURL+strings.TrimPrefix(path, "/"), output, flags, time.Duration(cfg.Timeout))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if output != "" {
+			page.Reports = append(page.Reports, report.LighthouseReport{
+				Run:  i + 1,
+				JSON: output + ".report.json",
+				HTML: output + ".report.html",
+			})
+		}
+		page.Runs = append(page.Runs, run.LighthouseScores)
+		audits = append(audits, run.audits)
+	}
+	if len(page.Runs) == 0 {
+		if lastErr == nil {
+			lastErr = classifyLighthouseError(ctx, ctx.Err(), nil, time.Duration(cfg.Timeout))
+		}
+		page.Error = lastErr.Error()
+		page.ErrorKind = lighthouseErrorKind(lastErr)
+		return page
+	}
+
+	page.LighthouseScores = combineScores(page.Runs, 50)
+	// Audits are kept from a single run so they match each other
+	for i, run := range page.Runs {
+		if run.Performance == page.Performance {
+			page.Audits = audits[i]
+			break
+		}
+	}
+	if runs == 1 {
+		page.Runs = nil
+		return page
+	}
+
+	page.Spread = &report.LighthouseScores{}
+	for _, c := range report.Categories {
+		var scores []int
+		for _, run := range page.Runs {
+			scores = append(scores, *c.Score(&run))
+		}
+		d := spread(scores)
+		*c.Score(page.Spread) = d
+		if cfg.MaxSpread > 0 && d > cfg.MaxSpread {
+			page.Unstable = true
+		}
+	}
+	return page
+}
+
+// combineScores returns the percentile p of each category score of runs,
+// and the matching percentile of each metric: since a low score and a high
+// metric are both bad, p 0 picks the lowest scores and highest metrics.
+// Metrics are combined over the runs that measured them.
+func combineScores(runs []report.LighthouseScores, p int) report.LighthouseScores {
+	var combined report.LighthouseScores
+	for _, c := range report.Categories {
+		var scores []int
+		for _, run := range runs {
+			scores = append(scores, *c.Score(&run))
+		}
+		*c.Score(&combined) = percentile(scores, p)
+	}
+
+	var missing []string
+	for _, m := range report.Metrics {
+		var values []float64
+		for _, run := range runs {
+			if run.Metrics.Has(m.Key) {
+				values = append(values, *m.Field(run.Metrics))
+			}
+		}
+		if len(values) == 0 {
+			missing = append(missing, m.Key)
+			continue
+		}
+		if combined.Metrics == nil {
+			combined.Metrics = &report.LighthouseMetrics{}
+		}
+		*m.Field(combined.Metrics) = percentile(values, 100-p)
+	}
+	if combined.Metrics != nil {
+		combined.Metrics.Missing = missing
+	}
+	return combined
+}
+
+// spread returns the difference between the highest and lowest score.
+func spread(scores []int) int {
+	if len(scores) == 0 {
+		return 0
+	}
+	return slices.Max(scores) - slices.Min(scores)
+}
+
+// percentile returns the nearest-rank percentile p of values, so p 0 is
+// the lowest value and p 50 the lower median.
+func percentile[T cmp.Ordered](values []T, p int) T {
+	var zero T
+	if len(values) == 0 {
+		return zero
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+type lighthouseJSON struct {
+	Categories map[string]lhrCategory `json:"categories"`
+	Audits     map[string]lhrAudit    `json:"audits"`
+	// RuntimeError is set when the page could not be audited.
+	RuntimeError *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"runtimeError"`
+}
+
+// lighthouseRun is what site-forge keeps of one Lighthouse run.
+type lighthouseRun struct {
+	report.LighthouseScores
+	audits []report.LighthouseAudit
+}
+
+// lighthouseFlags returns the Lighthouse flags that select the audited
+// categories and emulate the named form factor with the throttling and
+// screen settings of cfg.
+func lighthouseFlags(formFactor string, cfg config.LighthouseConfig) []string {
+	flags := []string{"--only-categories=" + strings.Join(cfg.Categories, ",")}
+	if formFactor == "desktop" {
+		flags = append(flags, "--preset=desktop")
+	}
+
+	ff := cfg.FormFactor(formFactor)
+	if ff.Throttling.Method != "" {
+		flags = append(flags, "--throttling-method="+ff.Throttling.Method)
+	}
+	settings := []struct {
+		name  string
+		value float64
+	}{
+		{"throttling.rttMs", ff.Throttling.RTT},
+		{"throttling.throughputKbps", ff.Throttling.ThroughputKbps},
+		{"throttling.cpuSlowdownMultiplier", ff.Throttling.CPUSlowdown},
+		{"throttling.requestLatencyMs", ff.Throttling.RequestLatency},
+		{"throttling.downloadThroughputKbps", ff.Throttling.DownloadThroughputKbps},
+		{"throttling.uploadThroughputKbps", ff.Throttling.UploadThroughputKbps},
+		{"screenEmulation.width", float64(ff.Screen.Width)},
+		{"screenEmulation.height", float64(ff.Screen.Height)},
+		{"screenEmulation.deviceScaleFactor", ff.Screen.DeviceScaleFactor},
+	}
+	for _, s := range settings {
+		if s.value > 0 {
+			flags = append(flags, fmt.Sprintf("--%s=%s", s.name, strconv.FormatFloat(s.value, 'f', -1, 64)))
+		}
+	}
+	if ff.Screen.Disabled {
+		flags = append(flags, "--screenEmulation.disabled")
+	}
+	return flags
+}
+
+// parseLighthouseJSON extracts the category scores, lab metrics and failing
+// audits of a Lighthouse JSON report.
+func parseLighthouseJSON(data []byte) (lighthouseRun, error) {
+	var result lighthouseJSON
+	if err := json.Unmarshal(data, &result); err != nil {
+		return lighthouseRun{}, fmt.Errorf("failed to parse lighthouse JSON: %v", err)
+	}
+	if e := result.RuntimeError; e != nil && e.Code != "" {
+		return lighthouseRun{}, runtimeError(e.Code, e.Message)
+	}
+
+	// Convert scores from 0-1 to 0-100, rounded as Lighthouse shows them
+	var scores report.LighthouseScores
+	for _, c := range report.Categories {
+		if cat, ok := result.Categories[c.ID]; ok && cat.Score != nil {
+			*c.Score(&scores) = int(math.Round(*cat.Score * 100))
+		}
+	}
+
+	var missing []string
+	for _, m := range report.Metrics {
+		audit, ok := result.Audits[m.Audit]
+		if !ok || audit.NumericValue == nil {
+			missing = append(missing, m.Key)
+			continue
+		}
+		if scores.Metrics == nil {
+			scores.Metrics = &report.LighthouseMetrics{}
+		}
+		*m.Field(scores.Metrics) = *audit.NumericValue
+	}
+	if scores.Metrics != nil {
+		scores.Metrics.Missing = missing
+	}
+	return lighthouseRun{LighthouseScores: scores, audits: failingAudits(result)}, nil
+}
+
+// Ensure the report package has the correct structure for lighthouse
+func init() {
+	_ = filepath.Join("", "")
+}
@@ -0,0 +1,164 @@
+package checks
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/misty-step/site-forge/report"
+)
+
+// passingAuditScore is the score from which Lighthouse shows an audit as
+// passing.
+const passingAuditScore = 0.9
+
+// maxAuditItems caps the offending nodes or resources kept per audit.
+const maxAuditItems = 10
+
+type lhrAudit struct {
+	ID               string          `json:"id"`
+	Title            string          `json:"title"`
+	Score            *float64        `json:"score"`
+	ScoreDisplayMode string          `json:"scoreDisplayMode"`
+	DisplayValue     string          `json:"displayValue"`
+	NumericValue     *float64        `json:"numericValue"`
+	Details          json.RawMessage `json:"details"`
+}
+
+type lhrCategory struct {
+	Score     *float64 `json:"score"`
+	AuditRefs []struct {
+		ID     string  `json:"id"`
+		Weight float64 `json:"weight"`
+	} `json:"auditRefs"`
+}
+
+// failingAudits returns the scored audits of the report's categories that
+// scored below passingAuditScore, worst first.
+func failingAudits(lhr lighthouseJSON) []report.LighthouseAudit {
+	var audits []report.LighthouseAudit
+	seen := make(map[string]bool)
+	for _, c := range report.Categories {
+		category, ok := lhr.Categories[c.ID]
+		if !ok {
+			continue
+		}
+		for _, ref := range category.AuditRefs {
+			a, ok := lhr.Audits[ref.ID]
+			if !ok || seen[ref.ID] || a.Score == nil || *a.Score >= passingAuditScore {
+				continue
+			}
+			switch a.ScoreDisplayMode {
+			case "binary", "numeric", "metricSavings":
+			default:
+				continue
+			}
+			seen[ref.ID] = true
+			audits = append(audits, report.LighthouseAudit{
+				ID:           ref.ID,
+				Title:        a.Title,
+				Category:     c.ID,
+				Weight:       ref.Weight,
+				Score:        *a.Score,
+				DisplayValue: a.DisplayValue,
+				Items:        auditItems(a.Details),
+			})
+		}
+	}
+	sortAudits(audits)
+	return audits
+}
+
+// sortAudits orders audits from the lowest score, breaking ties by the
+// weight of the audit in its category.
+func sortAudits(audits []report.LighthouseAudit) {
+	slices.SortStableFunc(audits, func(a, b report.LighthouseAudit) int {
+		if c := cmp.Compare(a.Score, b.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Weight, a.Weight)
+	})
+}
+
+// auditItems returns the selectors of the nodes and the URLs of the
+// resources listed in the details of an audit.
+func auditItems(details json.RawMessage) []string {
+	var d struct {
+		Items []map[string]json.RawMessage `json:"items"`
+	}
+	if len(details) == 0 || json.Unmarshal(details, &d) != nil {
+		return nil
+	}
+
+	var items []string
+	add := func(item string) {
+		if item != "" && len(items) < maxAuditItems && !slices.Contains(items, item) {
+			items = append(items, item)
+		}
+	}
+	for _, item := range d.Items {
+		keys := slices.Sorted(maps.Keys(item))
+		for _, key := range keys {
+			raw := item[key]
+			if key == "items" {
+				// Nested tables, such as the element of the LCP audit
+				for _, nested := range auditItems(json.RawMessage(`{"items":` + string(raw) + `}`)) {
+					add(nested)
+				}
+				continue
+			}
+			var url string
+			if key == "url" && json.Unmarshal(raw, &url) == nil {
+				add(url)
+				continue
+			}
+			var value struct {
+				Type     string `json:"type"`
+				Selector string `json:"selector"`
+				URL      string `json:"url"`
+			}
+			if json.Unmarshal(raw, &value) != nil {
+				continue
+			}
+			switch value.Type {
+			case "node":
+				add(value.Selector)
+			case "source-location":
+				add(value.URL)
+			}
+		}
+	}
+	return items
+}
+
+// mergeAudits combines the audits of several pages into one entry per
+// audit, keeping the lowest score and recording the pages it failed on.
+func mergeAudits(pages []report.LighthousePage) []report.LighthouseAudit {
+	var merged []report.LighthouseAudit
+	index := make(map[string]int)
+	for _, page := range pages {
+		for _, a := range page.Audits {
+			i, ok := index[a.ID]
+			if !ok {
+				index[a.ID] = len(merged)
+				a.Items = slices.Clone(a.Items)
+				a.Pages = []string{page.URL}
+				merged = append(merged, a)
+				continue
+			}
+			m := &merged[i]
+			if a.Score < m.Score {
+				m.Score, m.DisplayValue = a.Score, a.DisplayValue
+			}
+			for _, item := range a.Items {
+				if len(m.Items) < maxAuditItems && !slices.Contains(m.Items, item) {
+					m.Items = append(m.Items, item)
+				}
+			}
+			m.Pages = append(m.Pages, page.URL)
+		}
+	}
+	sortAudits(merged)
+	return merged
+}
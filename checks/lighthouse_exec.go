@@ -0,0 +1,251 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/misty-step/site-forge/report"
+)
+
+// killGrace is how long Lighthouse gets to exit after it is interrupted
+// before its process group is killed.
+const killGrace = 5 * time.Second
+
+// LighthouseError is an error that stopped a Lighthouse run, classified by
+// its likely cause.
+type LighthouseError struct {
+	// Kind is one of the report.Lighthouse* error kinds.
+	Kind string
+	Err  error
+}
+
+func (e *LighthouseError) Error() string { return e.Err.Error() }
+func (e *LighthouseError) Unwrap() error { return e.Err }
+
+// lighthouseErrorKind returns the kind of err, or report.LighthouseRuntime
+// if it was not classified.
+func lighthouseErrorKind(err error) string {
+	var lerr *LighthouseError
+	if errors.As(err, &lerr) {
+		return lerr.Kind
+	}
+	return report.LighthouseRuntime
+}
+
+// outputPatterns map messages printed by npm, chrome-launcher and
+// Lighthouse to the kind of error they reveal, most specific first.
+var outputPatterns = []struct {
+	substr string
+	kind   string
+}{
+	{"could not determine executable to run", report.LighthouseNotInstalled},
+	{"npm ERR! 404", report.LighthouseNotInstalled},
+	{"npm error 404", report.LighthouseNotInstalled},
+	{"No Chrome installations found", report.LighthouseChromeLaunch},
+	{"CHROME_PATH environment variable must be set", report.LighthouseChromeLaunch},
+	{"Unable to connect to Chrome", report.LighthouseChromeLaunch},
+	{"Chrome could not be launched", report.LighthouseChromeLaunch},
+	{"PROTOCOL_TIMEOUT", report.LighthouseTimeout},
+	{"net::ERR_", report.LighthouseNavigation},
+	{"Unable to reliably load the page", report.LighthouseNavigation},
+	{"did not paint any content", report.LighthouseNavigation},
+}
+
+// navigationErrors lists the codes of Lighthouse runtime errors caused by
+// loading the page rather than by Lighthouse itself.
+var navigationErrors = []string{
+	"NO_FCP",
+	"NO_NAVSTART",
+	"PAGE_HUNG",
+	"DNS_FAILURE",
+	"FAILED_DOCUMENT_REQUEST",
+	"ERRORED_DOCUMENT_REQUEST",
+	"INSECURE_DOCUMENT_REQUEST",
+	"NOT_HTML",
+	"CHROME_INTERSTITIAL_ERROR",
+	"PAGE_LOAD_TIMEOUT",
+}
+
+// classifyLighthouseError returns the error of a Lighthouse command that
+// failed with err and printed output. ctx is the context the command ran
+// with, and timeout its time limit.
+func classifyLighthouseError(ctx context.Context, err error, output []byte, timeout time.Duration) *LighthouseError {
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return &LighthouseError{report.LighthouseNotInstalled, fmt.Errorf("lighthouse not installed: %v", err)}
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &LighthouseError{report.LighthouseTimeout, fmt.Errorf("lighthouse timed out after %s", timeout)}
+	case errors.Is(ctx.Err(), context.Canceled):
+		return &LighthouseError{report.LighthouseRuntime, errors.New("lighthouse canceled")}
+	}
+
+	text := string(output)
+	for _, p := range outputPatterns {
+		if line, ok := lineContaining(text, p.substr); ok {
+			return &LighthouseError{p.kind, fmt.Errorf("%s: %s", errorKindLabel(p.kind), line)}
+		}
+	}
+	return &LighthouseError{report.LighthouseRuntime, fmt.Errorf("lighthouse error: %v, output: %s", err, strings.TrimSpace(text))}
+}
+
+// runtimeError returns the error recorded in the runtimeError field of a
+// Lighthouse report.
+func runtimeError(code, message string) *LighthouseError {
+	kind := report.LighthouseRuntime
+	switch {
+	case code == "PROTOCOL_TIMEOUT":
+		kind = report.LighthouseTimeout
+	case strings.HasPrefix(code, "CHROME_"), slices.Contains(navigationErrors, code):
+		kind = report.LighthouseNavigation
+	}
+	return &LighthouseError{kind, fmt.Errorf("%s: %s (%s)", errorKindLabel(kind), message, code)}
+}
+
+// errorKindLabel describes an error kind at the start of a message.
+func errorKindLabel(kind string) string {
+	switch kind {
+	case report.LighthouseNotInstalled:
+		return "lighthouse not installed"
+	case report.LighthouseChromeLaunch:
+		return "Chrome failed to launch"
+	case report.LighthouseNavigation:
+		return "page failed to load"
+	case report.LighthouseTimeout:
+		return "lighthouse timed out"
+	}
+	return "lighthouse error"
+}
+
+// lineContaining returns the trimmed first line of text containing substr.
+func lineContaining(text, substr string) (string, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, substr) {
+			return strings.TrimSpace(line), true
+		}
+	}
+	return "", false
+}
+
+// lighthouseVersion returns the version of the installed Lighthouse,
+// giving up after timeout. Errors are *LighthouseError.
+func (t Tools) lighthouseVersion(ctx context.Context, timeout time.Duration) (string, error) {
+	if t.Lighthouse == "" {
+		return "", notInstalled()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := t.lighthouseCommand(ctx, "--version")
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		killProcessGroup(cmd)
+	}
+	if err != nil {
+		return "", probeError(ctx, err, output, timeout)
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return version, nil
+}
+
+// probeError returns the error of a lighthouse --version that failed with
+// err and printed output. A Lighthouse that cannot print its version, even
+// within timeout, is not usable, so it counts as not installed.
+func probeError(ctx context.Context, err error, output []byte, timeout time.Duration) *LighthouseError {
+	lerr := classifyLighthouseError(ctx, err, output, timeout)
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return lerr
+	case lerr.Kind == report.LighthouseTimeout:
+		lerr = notInstalled()
+		lerr.Err = fmt.Errorf("%v; lighthouse --version did not answer within %s", lerr.Err, timeout)
+		return lerr
+	case lerr.Kind == report.LighthouseRuntime, lerr.Kind == report.LighthouseNotInstalled:
+		return notInstalled()
+	}
+	return lerr
+}
+
+// notInstalled returns the error of a missing Lighthouse.
+func notInstalled() *LighthouseError {
+	return &LighthouseError{report.LighthouseNotInstalled, fmt.Errorf("lighthouse not installed (run: npm install -g lighthouse, or set %s)", EnvLighthouse)}
+}
+
+// runLighthouse audits url with flags using tools, giving up after
+// timeout. The audit runs in the Chrome at tools.ChromePort, or else in a
+// Chrome launched for it and stopped afterwards. If reportPath is set, the JSON and HTML reports are kept as
+// reportPath.report.json and reportPath.report.html; otherwise only a
+// temporary JSON report is written. Errors are *LighthouseError.
+func runLighthouse(ctx context.Context, tools Tools, url, reportPath string, flags []string, timeout time.Duration) (lighthouseRun, error) {
+	args := []string{url}
+	jsonPath := reportPath + ".report.json"
+	if reportPath == "" {
+		// Create temp file for JSON output
+		tmpFile, err := os.CreateTemp("", "lighthouse-*.json")
+		if err != nil {
+			return lighthouseRun{}, &LighthouseError{report.LighthouseRuntime, err}
+		}
+		jsonPath = tmpFile.Name()
+		tmpFile.Close()
+		defer os.Remove(jsonPath)
+		args = append(args, "--output=json", "--output-path="+jsonPath)
+	} else {
+		args = append(args, "--output=json", "--output=html", "--output-path="+reportPath)
+	}
+
+	// Lighthouse connects to a Chrome site-forge launched rather than
+	// launching its own, which would outlive a Lighthouse that is killed
+	port := tools.ChromePort
+	if port == 0 {
+		b, err := StartBrowser(tools, 1)
+		if err != nil {
+			return lighthouseRun{}, &LighthouseError{report.LighthouseChromeLaunch, fmt.Errorf("%s: %v", errorKindLabel(report.LighthouseChromeLaunch), err)}
+		}
+		defer b.Close()
+		port = b.Port
+	}
+
+	// Run lighthouse
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	args = append(args, fmt.Sprintf("--port=%d", port), "--quiet")
+	cmd := tools.lighthouseCommand(ctx, append(args, flags...)...)
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		killProcessGroup(cmd)
+	}
+	if err != nil {
+		lerr := classifyLighthouseError(ctx, err, output, timeout)
+		// A page that failed to load still gets a report saying why
+		if lerr.Kind == report.LighthouseRuntime {
+			if data, err := os.ReadFile(jsonPath); err == nil {
+				var rerr *LighthouseError
+				if _, err := parseLighthouseJSON(data); errors.As(err, &rerr) {
+					return lighthouseRun{}, rerr
+				}
+			}
+		}
+		return lighthouseRun{}, lerr
+	}
+
+	// Read and parse result
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return lighthouseRun{}, &LighthouseError{report.LighthouseRuntime, fmt.Errorf("failed to read lighthouse output: %v", err)}
+	}
+	run, err := parseLighthouseJSON(data)
+	if err != nil {
+		var lerr *LighthouseError
+		if !errors.As(err, &lerr) {
+			lerr = &LighthouseError{report.LighthouseRuntime, err}
+		}
+		return lighthouseRun{}, lerr
+	}
+	return run, nil
+}
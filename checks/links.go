@@ -0,0 +1,180 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/misty-step/site-forge/report"
+)
+
+type linksCheck struct{}
+
+func (linksCheck) Name() string           { return "links" }
+func (linksCheck) Dependencies() []string { return nil }
+func (linksCheck) Severity() Severity     { return SeverityError }
+
+func (linksCheck) Run(ctx context.Context, env *Env) report.Result {
+	return CheckLinks(env)
+}
+
+// CheckLinks verifies that every internal <a href> and <area href> points
+// to a page that exists, and that #fragment targets exist in that page
+func CheckLinks(env *Env) report.LinksResult {
+	distDir := env.Dir
+	result := report.LinksResult{
+		Status: "PASS",
+	}
+
+	htmlFiles, err := env.HTMLFiles()
+	if err != nil {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("Error finding HTML files: %v", err)
+		return result
+	}
+
+	resolver := newSiteResolver(distDir, env.Config.BasePath)
+	anchors := make(map[string]map[string]bool)
+
+	for _, htmlFile := range htmlFiles {
+		data, err := os.ReadFile(htmlFile)
+		if err != nil {
+			result.Status = "FAIL"
+			result.Details = fmt.Sprintf("Error reading %s: %v", htmlFile, err)
+			return result
+		}
+		rel, _ := filepath.Rel(distDir, htmlFile)
+
+		elements := scanElements(data)
+		base := resolver.documentBase(resolver.pageURL(htmlFile), baseHref(elements))
+
+		for _, el := range elements {
+			if el.Data != "a" && el.Data != "area" {
+				continue
+			}
+			href, ok := el.attr("href")
+			if !ok {
+				continue
+			}
+
+			u, local := resolver.resolveURL(base, href)
+			if !local {
+				continue
+			}
+			result.Total++
+
+			broken := func(reason string) {
+				result.Broken = append(result.Broken, report.BrokenLink{
+					Page:   filepath.ToSlash(rel),
+					Line:   el.Line,
+					URL:    href,
+					Reason: reason,
+				})
+			}
+
+			target := resolver.filePath(u.Path)
+			if target == "" {
+				broken("outside the site's base path")
+				continue
+			}
+			page := findLinkTarget(target, strings.HasSuffix(u.Path, "/"))
+			if page == "" {
+				broken("target not found")
+				continue
+			}
+			fragment := u.Fragment
+			if fragment == "" || strings.EqualFold(fragment, "top") || !isHTML(page) {
+				continue
+			}
+			ids, ok := anchors[page]
+			if !ok {
+				ids = documentAnchors(page)
+				anchors[page] = ids
+			}
+			if !ids[fragment] {
+				broken(fmt.Sprintf("no element with id or name %q", fragment))
+			}
+		}
+	}
+
+	if len(result.Broken) > 0 {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("%d of %d internal links broken", len(result.Broken), result.Total)
+	} else {
+		result.Details = fmt.Sprintf("%d/%d internal links verified", result.Total, result.Total)
+	}
+
+	return result
+}
+
+// baseHref returns the href of the first <base> element with one.
+func baseHref(elements []element) string {
+	for _, el := range elements {
+		if el.Data != "base" {
+			continue
+		}
+		if href, ok := el.attr("href"); ok {
+			return href
+		}
+	}
+	return ""
+}
+
+// findLinkTarget returns the file a static host serves for the URL mapped to
+// path, following the directory index (/about/ -> about/index.html) and clean
+// URL (/about -> about.html) conventions. dir is true if the URL ends in a
+// slash. It returns "" if there is none.
+func findLinkTarget(path string, dir bool) string {
+	if dir {
+		index := filepath.Join(path, "index.html")
+		if fileExists(index) {
+			return index
+		}
+		return ""
+	}
+	if info, err := os.Stat(path); err == nil {
+		if !info.IsDir() {
+			return path
+		}
+		if index := filepath.Join(path, "index.html"); fileExists(index) {
+			return index
+		}
+		return ""
+	}
+	if fileExists(path + ".html") {
+		return path + ".html"
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func isHTML(path string) bool {
+	return strings.HasSuffix(path, ".html") || strings.HasSuffix(path, ".htm")
+}
+
+// documentAnchors returns the fragment targets of an HTML file: the values
+// of every id attribute and of name attributes on <a> elements.
+func documentAnchors(path string) map[string]bool {
+	ids := make(map[string]bool)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ids
+	}
+	for _, el := range scanElements(data) {
+		if id, ok := el.attr("id"); ok {
+			ids[id] = true
+		}
+		if el.Data == "a" {
+			if name, ok := el.attr("name"); ok {
+				ids[name] = true
+			}
+		}
+	}
+	return ids
+}
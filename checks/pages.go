@@ -0,0 +1,211 @@
+package checks
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SitePages returns the URL paths of the site's pages, relative to the base
+// path: the pages listed in sitemap.xml, or the HTML files of the site if it
+// has no sitemap.
+func (e *Env) SitePages() ([]string, error) {
+	pages, err := sitemapPages(e.Dir, e.Config.BasePath)
+	if err != nil || len(pages) > 0 {
+		return pages, err
+	}
+
+	return e.htmlPages()
+}
+
+// htmlPages returns the URL paths of the site's HTML files, other than
+// 404.html, relative to the base path.
+func (e *Env) htmlPages() ([]string, error) {
+	files, err := e.HTMLFiles()
+	if err != nil {
+		return nil, err
+	}
+	var pages []string
+	for _, f := range files {
+		rel, err := filepath.Rel(e.Dir, f)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "404.html" {
+			continue
+		}
+		if rel == "index.html" || strings.HasSuffix(rel, "/index.html") {
+			rel = strings.TrimSuffix(rel, "index.html")
+		}
+		pages = append(pages, "/"+rel)
+	}
+	sort.Strings(pages)
+	return pages, nil
+}
+
+// ScreenshotPages returns the URL paths of the pages to capture: every
+// HTML page if screenshots.all_pages is set, otherwise screenshots.pages
+// or, if that is empty, the configured pages.
+func (e *Env) ScreenshotPages() ([]string, error) {
+	cfg := e.Config.Screenshots
+	switch {
+	case cfg.AllPages:
+		return e.htmlPages()
+	case len(cfg.Pages) > 0:
+		return cfg.Pages, nil
+	case len(e.Config.Pages) > 0:
+		return e.Config.Pages, nil
+	}
+	return []string{"/"}, nil
+}
+
+type sitemapXML struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// sitemapPages returns the pages listed in the sitemap.xml of dir, following
+// a sitemap index into the sitemaps it lists on the same site. It returns
+// nil if there is no sitemap.
+func sitemapPages(dir, basePath string) ([]string, error) {
+	base := normalizeBasePath(basePath)
+	seen := make(map[string]bool)
+	var pages []string
+
+	var read func(file string) error
+	read = func(file string) error {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var sm sitemapXML
+		if err := xml.Unmarshal(data, &sm); err != nil {
+			return err
+		}
+		for _, u := range sm.URLs {
+			if page, ok := sitePath(u.Loc, base); ok && !seen[page] {
+				seen[page] = true
+				pages = append(pages, page)
+			}
+		}
+		for _, s := range sm.Sitemaps {
+			page, ok := sitePath(s.Loc, base)
+			if !ok || seen[page] {
+				continue
+			}
+			seen[page] = true
+			child := filepath.Join(dir, filepath.FromSlash(page))
+			if fileExists(child) {
+				if err := read(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	file := filepath.Join(dir, "sitemap.xml")
+	if !fileExists(file) {
+		return nil, nil
+	}
+	if err := read(file); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// sitePath maps an absolute sitemap URL to a path relative to the base path.
+func sitePath(loc, base string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(loc))
+	if err != nil || u.Path == "" && u.Host == "" {
+		return "", false
+	}
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	if p+"/" == base {
+		return "/", true
+	}
+	if !strings.HasPrefix(p, base) {
+		return "", false
+	}
+	return "/" + strings.TrimPrefix(p, base), true
+}
+
+// samplePages returns pages followed by candidates spread evenly across the
+// list, up to n pages in total. The pick depends only on the candidate
+// list, so repeated runs audit the same pages.
+func samplePages(pages, candidates []string, n int) []string {
+	picked := append([]string(nil), pages...)
+	seen := make(map[string]bool, len(pages))
+	for _, p := range pages {
+		seen[p] = true
+	}
+	var rest []string
+	for _, c := range candidates {
+		if !seen[c] {
+			seen[c] = true
+			rest = append(rest, c)
+		}
+	}
+
+	want := n - len(picked)
+	if want <= 0 {
+		return picked
+	}
+	if want >= len(rest) {
+		return append(picked, rest...)
+	}
+	for i := 0; i < want; i++ {
+		picked = append(picked, rest[i*len(rest)/want])
+	}
+	return picked
+}
+
+// pageSlugs returns a file name for each page that depends only on its
+// path, such as "index" for "/" and "blog-first-post" for
+// "/blog/first-post/". Pages whose names would collide get a numeric suffix
+// in list order.
+func pageSlugs(pages []string) []string {
+	slugs := make([]string, len(pages))
+	used := make(map[string]bool)
+	for i, page := range pages {
+		slug := pageSlug(page)
+		for n := 2; used[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", pageSlug(page), n)
+		}
+		used[slug] = true
+		slugs[i] = slug
+	}
+	return slugs
+}
+
+func pageSlug(page string) string {
+	p := strings.Trim(page, "/")
+	if p == "" {
+		return "index"
+	}
+	var b strings.Builder
+	for _, r := range p {
+		switch {
+		case r == '/':
+			b.WriteByte('-')
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package checks
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// maxColorDelta is the largest perceived difference colorDelta returns,
+// between black and white.
+const maxColorDelta = 35215
+
+// Colors of the diff image. Changed pixels shade from orange, for barely
+// noticeable changes, to red; anti-aliasing changes, which are not
+// counted, are blue; the rest is the first image faded to gray.
+var (
+	diffLowColor = color.NRGBA{255, 170, 0, 255}
+	diffAAColor  = color.NRGBA{80, 160, 255, 255}
+)
+
+// diffFade is the opacity of the unchanged pixels in the diff image.
+const diffFade = 0.1
+
+// pixelDiff is the outcome of a comparison of two images.
+type pixelDiff struct {
+	// Mismatched is the number of pixels that differ, out of Total, the
+	// area covered by either image. Pixels outside one of the images
+	// differ.
+	Mismatched int
+	Total      int
+	// Image highlights the differences over the first image.
+	Image *image.NRGBA
+}
+
+// Ratio returns the share of pixels that differ.
+func (d pixelDiff) Ratio() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return float64(d.Mismatched) / float64(d.Total)
+}
+
+// diffImages compares a and b pixel by pixel, in the way of pixelmatch:
+// pixels differ when their perceived color difference, in the YIQ color
+// space, exceeds threshold, from 0 to 1, unless the difference looks like
+// anti-aliasing, which varies between renderers.
+func diffImages(a, b image.Image, threshold float64) pixelDiff {
+	img1, img2 := toNRGBA(a), toNRGBA(b)
+	w1, h1 := img1.Rect.Dx(), img1.Rect.Dy()
+	w2, h2 := img2.Rect.Dx(), img2.Rect.Dy()
+	width, height := min(w1, w2), min(h1, h2)
+	out := image.NewNRGBA(image.Rect(0, 0, max(w1, w2), max(h1, h2)))
+	d := pixelDiff{Total: out.Rect.Dx() * out.Rect.Dy(), Image: out}
+
+	maxDelta := maxColorDelta * threshold * threshold
+	for y := 0; y < out.Rect.Dy(); y++ {
+		for x := 0; x < out.Rect.Dx(); x++ {
+			if x >= width || y >= height {
+				d.Mismatched++
+				out.SetNRGBA(x, y, heatColor(1))
+				continue
+			}
+			delta := colorDelta(img1, img2, x, y, x, y, false)
+			switch {
+			case math.Abs(delta) <= maxDelta:
+				v := uint8(math.Round(blend(brightness(img1.NRGBAAt(x, y)), diffFade*float64(img1.NRGBAAt(x, y).A)/255)))
+				out.SetNRGBA(x, y, color.NRGBA{v, v, v, 255})
+			case antialiased(img1, img2, x, y, width, height) || antialiased(img2, img1, x, y, width, height):
+				out.SetNRGBA(x, y, diffAAColor)
+			default:
+				d.Mismatched++
+				out.SetNRGBA(x, y, heatColor((math.Abs(delta)-maxDelta)/(maxColorDelta-maxDelta)))
+			}
+		}
+	}
+	return d
+}
+
+// heatColor returns the color of a changed pixel, from orange for t = 0 to
+// red for t = 1.
+func heatColor(t float64) color.NRGBA {
+	t = min(max(t, 0), 1)
+	return color.NRGBA{255, uint8(math.Round(float64(diffLowColor.G) * (1 - t))), 0, 255}
+}
+
+// toNRGBA returns img as an NRGBA image with its origin at (0, 0).
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok && n.Rect.Min == (image.Point{}) {
+		return n
+	}
+	b := img.Bounds()
+	n := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(n, n.Rect, img, b.Min, draw.Src)
+	return n
+}
+
+// antialiased reports whether the pixel at (x1, y1) of img looks like
+// anti-aliasing: it has at most two neighbors of the same brightness, and
+// its darkest or brightest neighbor is in a flat area of both images, such
+// as the inside of a glyph.
+func antialiased(img, img2 *image.NRGBA, x1, y1, width, height int) bool {
+	x0, y0 := max(x1-1, 0), max(y1-1, 0)
+	x2, y2 := min(x1+1, width-1), min(y1+1, height-1)
+	zeroes := 0
+	if x1 == x0 || x1 == x2 || y1 == y0 || y1 == y2 {
+		zeroes = 1
+	}
+	var minDelta, maxDelta float64
+	var minX, minY, maxX, maxY int
+	for x := x0; x <= x2; x++ {
+		for y := y0; y <= y2; y++ {
+			if x == x1 && y == y1 {
+				continue
+			}
+			delta := colorDelta(img, img, x1, y1, x, y, true)
+			switch {
+			case delta == 0:
+				zeroes++
+				if zeroes > 2 {
+					return false
+				}
+			case delta < minDelta:
+				minDelta, minX, minY = delta, x, y
+			case delta > maxDelta:
+				maxDelta, maxX, maxY = delta, x, y
+			}
+		}
+	}
+	if minDelta == 0 || maxDelta == 0 {
+		return false
+	}
+	return hasManySiblings(img, minX, minY, width, height) && hasManySiblings(img2, minX, minY, width, height) ||
+		hasManySiblings(img, maxX, maxY, width, height) && hasManySiblings(img2, maxX, maxY, width, height)
+}
+
+// hasManySiblings reports whether more than two neighbors of the pixel at
+// (x1, y1) have its exact color.
+func hasManySiblings(img *image.NRGBA, x1, y1, width, height int) bool {
+	x0, y0 := max(x1-1, 0), max(y1-1, 0)
+	x2, y2 := min(x1+1, width-1), min(y1+1, height-1)
+	zeroes := 0
+	if x1 == x0 || x1 == x2 || y1 == y0 || y1 == y2 {
+		zeroes = 1
+	}
+	c := img.NRGBAAt(x1, y1)
+	for x := x0; x <= x2; x++ {
+		for y := y0; y <= y2; y++ {
+			if x == x1 && y == y1 {
+				continue
+			}
+			if img.NRGBAAt(x, y) == c {
+				zeroes++
+			}
+			if zeroes > 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// colorDelta returns the perceived difference between the pixel at (x1, y1)
+// of img1 and the one at (x2, y2) of img2, both blended over white: the
+// YIQ distance, or the difference in brightness alone if yOnly is set. It
+// is negative when the first pixel is brighter.
+func colorDelta(img1, img2 *image.NRGBA, x1, y1, x2, y2 int, yOnly bool) float64 {
+	c1, c2 := img1.NRGBAAt(x1, y1), img2.NRGBAAt(x2, y2)
+	if c1 == c2 {
+		return 0
+	}
+	r1, g1, b1 := overWhite(c1)
+	r2, g2, b2 := overWhite(c2)
+
+	bright1, bright2 := rgb2y(r1, g1, b1), rgb2y(r2, g2, b2)
+	y := bright1 - bright2
+	if yOnly {
+		return y
+	}
+	i := rgb2i(r1, g1, b1) - rgb2i(r2, g2, b2)
+	q := rgb2q(r1, g1, b1) - rgb2q(r2, g2, b2)
+	delta := 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+	if bright1 > bright2 {
+		return -delta
+	}
+	return delta
+}
+
+// overWhite returns the channels of c blended over a white background.
+func overWhite(c color.NRGBA) (r, g, b float64) {
+	r, g, b = float64(c.R), float64(c.G), float64(c.B)
+	if c.A < 255 {
+		a := float64(c.A) / 255
+		r, g, b = blend(r, a), blend(g, a), blend(b, a)
+	}
+	return r, g, b
+}
+
+// brightness returns the brightness of c, ignoring its alpha.
+func brightness(c color.NRGBA) float64 {
+	return rgb2y(float64(c.R), float64(c.G), float64(c.B))
+}
+
+func blend(c, a float64) float64 { return 255 + (c-255)*a }
+
+func rgb2y(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func rgb2i(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func rgb2q(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package checks
+
+import "os/exec"
+
+// setProcessGroup kills cmd when its context is done. Child processes are
+// not tracked on this platform.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.WaitDelay = killGrace
+}
+
+func killProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package checks
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup runs cmd in a process group of its own and, when its
+// context is done, interrupts the whole group so Lighthouse can disconnect
+// from Chrome. Whatever has not exited after killGrace is killed.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+	}
+	cmd.WaitDelay = killGrace
+}
+
+// killProcessGroup kills the processes left in the group of cmd, such as
+// those Node started. Chrome is not among them: site-forge launches and
+// stops the Chrome that Lighthouse connects to.
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,174 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+// pollInterval is how often conditions without an event to wait for, such
+// as the images being complete, are checked.
+const pollInterval = 100 * time.Millisecond
+
+// imagesComplete loads the lazy images of the page, which would otherwise
+// wait to be scrolled into view, and reports whether every image is done
+// loading, successfully or not.
+const imagesComplete = `(() => {
+	const images = Array.from(document.images);
+	for (const img of images) {
+		if (img.loading === "lazy") img.loading = "eager";
+	}
+	return images.every(img => img.complete);
+})()`
+
+// readiness waits for a page loaded in a tab to meet the conditions of a
+// config.WaitConfig, and records how it went.
+type readiness struct {
+	cfg  config.WaitConfig
+	idle chan struct{}
+
+	// Result is the outcome of the last wait.
+	Result report.Readiness
+}
+
+func newReadiness(cfg config.WaitConfig) *readiness {
+	return &readiness{cfg: cfg, idle: make(chan struct{})}
+}
+
+// listen watches the tab for the network to go idle after the next
+// navigation. It must run before the navigation.
+func (r *readiness) listen() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		// The main frame of a tab has the id of its target
+		mainFrame := cdp.FrameID(chromedp.FromContext(ctx).Target.TargetID)
+		navigated := false
+		var once sync.Once
+		chromedp.ListenTarget(ctx, func(ev any) {
+			e, ok := ev.(*page.EventLifecycleEvent)
+			if !ok || e.FrameID != mainFrame {
+				return
+			}
+			switch e.Name {
+			case "init":
+				navigated = true
+			case "networkIdle":
+				if navigated {
+					once.Do(func() { close(r.idle) })
+				}
+			}
+		})
+		return page.SetLifecycleEventsEnabled(true).Do(ctx)
+	})
+}
+
+// wait awaits the conditions in order, giving up on those left when the
+// maximum wait runs out, and records the outcome in Result. It returns an
+// error only if ctx is done or a condition cannot be evaluated, such as a
+// predicate that throws.
+func (r *readiness) wait() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		waitCtx, cancel := context.WithTimeout(ctx, time.Duration(r.cfg.MaxWait))
+		defer cancel()
+
+		start := time.Now()
+		r.Result = report.Readiness{}
+		for _, c := range r.conditions() {
+			err := c.wait(waitCtx)
+			switch {
+			case ctx.Err() != nil:
+				return ctx.Err()
+			case err != nil && waitCtx.Err() == nil:
+				return fmt.Errorf("wait for %s: %v", c.name, err)
+			}
+			r.Result.Conditions = append(r.Result.Conditions, report.ReadyCondition{
+				Name: c.name,
+				Met:  err == nil,
+				Wait: milliseconds(time.Since(start)),
+			})
+		}
+		r.Result.Wait = milliseconds(time.Since(start))
+		return nil
+	})
+}
+
+// readyCondition is a condition a page is waited on to meet.
+type readyCondition struct {
+	name string
+	wait func(ctx context.Context) error
+}
+
+// conditions returns the configured conditions in the order they are
+// awaited: the listed ones, then the selector and the predicate.
+func (r *readiness) conditions() []readyCondition {
+	var conds []readyCondition
+	for _, name := range r.cfg.Conditions {
+		switch name {
+		case "network_idle":
+			conds = append(conds, readyCondition{name, func(ctx context.Context) error {
+				select {
+				case <-r.idle:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}})
+		case "fonts":
+			conds = append(conds, readyCondition{name, func(ctx context.Context) error {
+				var ok bool
+				return chromedp.Evaluate(`document.fonts.ready.then(() => true)`, &ok, awaitPromise).Do(ctx)
+			}})
+		case "images":
+			conds = append(conds, readyCondition{name, func(ctx context.Context) error {
+				return poll(ctx, imagesComplete)
+			}})
+		}
+	}
+	if sel := r.cfg.Selector; sel != "" {
+		conds = append(conds, readyCondition{"selector", func(ctx context.Context) error {
+			return chromedp.WaitVisible(sel, chromedp.ByQuery).Do(ctx)
+		}})
+	}
+	if pred := r.cfg.Predicate; pred != "" {
+		conds = append(conds, readyCondition{"predicate", func(ctx context.Context) error {
+			return poll(ctx, "Promise.resolve(("+pred+"\n)).then(Boolean)")
+		}})
+	}
+	return conds
+}
+
+// poll evaluates expression, which must return a boolean or a promise of
+// one, until it is true.
+func poll(ctx context.Context, expression string) error {
+	for {
+		var ok bool
+		if err := chromedp.Evaluate(expression, &ok, awaitPromise).Do(ctx); err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// awaitPromise makes an evaluation wait for the promise it returns.
+func awaitPromise(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+	return p.WithAwaitPromise(true)
+}
+
+// milliseconds returns d in milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
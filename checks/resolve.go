@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// siteOrigin is the origin pages are resolved against. URLs that resolve to
+// another origin are external to the site and are not checked.
+var siteOrigin = &url.URL{Scheme: "http", Host: "site-forge.invalid"}
+
+// siteResolver maps URLs referenced by pages onto files in the site
+// directory the way a browser would request them from the deployed site.
+type siteResolver struct {
+	dir string
+	// basePath is the URL path the site is served under, with leading and
+	// trailing slashes ("/" for a site at the root of its domain).
+	basePath string
+}
+
+func newSiteResolver(dir, basePath string) siteResolver {
+	return siteResolver{dir: dir, basePath: normalizeBasePath(basePath)}
+}
+
+func normalizeBasePath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return "/"
+	}
+	return "/" + p + "/"
+}
+
+// pageURL returns the URL the HTML file at path is served from.
+func (r siteResolver) pageURL(path string) *url.URL {
+	rel, err := filepath.Rel(r.dir, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+	u := *siteOrigin
+	u.Path = r.basePath + filepath.ToSlash(rel)
+	return &u
+}
+
+// documentBase returns the base URL of a page: its <base href> resolved
+// against the page URL if present, otherwise the page URL itself.
+func (r siteResolver) documentBase(page *url.URL, baseHref string) *url.URL {
+	if baseHref == "" {
+		return page
+	}
+	base, err := page.Parse(baseHref)
+	if err != nil {
+		return page
+	}
+	return base
+}
+
+// resolveURL resolves ref against base and reports whether the result is
+// on the site's origin, rather than another origin or a scheme such as
+// data: or mailto:. Map the path of the result to a file with filePath.
+func (r siteResolver) resolveURL(base *url.URL, ref string) (*url.URL, bool) {
+	ref = strings.TrimSpace(ref)
+	u, err := url.Parse(ref)
+	if err != nil {
+		// Unparseable references are checked as literal paths
+		u = &url.URL{Path: ref}
+	}
+	resolved := base.ResolveReference(u)
+	if resolved.Scheme != siteOrigin.Scheme || resolved.Host != siteOrigin.Host {
+		return nil, false
+	}
+	return resolved, true
+}
+
+// filePath maps a decoded URL path on the site's origin to a file path, or
+// "" if the path is outside the base path.
+func (r siteResolver) filePath(urlPath string) string {
+	if urlPath+"/" == r.basePath {
+		urlPath = r.basePath
+	}
+	if !strings.HasPrefix(urlPath, r.basePath) {
+		return ""
+	}
+	rel := strings.TrimPrefix(urlPath, r.basePath)
+	return filepath.Join(r.dir, filepath.FromSlash(rel))
+}
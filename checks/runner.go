@@ -0,0 +1,105 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+// Runner executes the checks of a registry in dependency order.
+type Runner struct {
+	Registry *Registry
+	// FailFast stops the run at the first failing check with error
+	// severity instead of running every check whose dependencies passed.
+	FailFast bool
+
+	// OnStart is called before each check runs.
+	OnStart func(c Check, index, total int)
+	// OnFinish is called with the result of each check, including checks
+	// that were skipped without running.
+	OnFinish func(c Check, result report.Result)
+}
+
+// Run executes the checks and records every result in r, so the report is
+// complete even when checks fail. A check whose dependencies did not pass is
+// recorded as BLOCKED. Overall is set to PASS only if no check with error
+// severity failed.
+func (rn *Runner) Run(ctx context.Context, env *Env, r *report.Report) error {
+	ordered, err := rn.Registry.Ordered()
+	if err != nil {
+		return err
+	}
+	if env.Config == nil {
+		env.Config = config.Default()
+	}
+	if env.Results == nil {
+		env.Results = report.ReportChecks{}
+	}
+
+	r.Overall = report.StatusPass
+	stopped := ""
+	for i, c := range ordered {
+		if rn.OnStart != nil {
+			rn.OnStart(c, i, len(ordered))
+		}
+
+		var result report.Result
+		if stopped != "" {
+			result = report.BasicResult{
+				Status:  report.StatusSkip,
+				Details: fmt.Sprintf("not run (%s failed, fail-fast)", stopped),
+			}
+		} else if !env.Config.Enabled(c.Name()) {
+			result = report.BasicResult{
+				Status:  report.StatusSkip,
+				Details: "disabled in config",
+			}
+		} else if dep, status := unmetDependency(c, env.Results); dep != "" {
+			result = report.BasicResult{
+				Status:  report.StatusBlocked,
+				Details: fmt.Sprintf("requires %s, which is %s", dep, status),
+			}
+		} else {
+			result = c.Run(ctx, env)
+		}
+
+		env.Results[c.Name()] = result
+		r.Add(c.Name(), result)
+		if rn.OnFinish != nil {
+			rn.OnFinish(c, result)
+		}
+
+		if result.CheckStatus() == report.StatusFail && severity(c, env) == SeverityError {
+			r.Overall = report.StatusFail
+			if rn.FailFast {
+				stopped = c.Name()
+			}
+		}
+	}
+	return nil
+}
+
+// severity returns the severity of c, honoring overrides from the config.
+func severity(c Check, env *Env) Severity {
+	if s := env.Config.Checks[c.Name()].Severity; s != "" {
+		return Severity(s)
+	}
+	return c.Severity()
+}
+
+// unmetDependency returns the first dependency of c that did not pass,
+// along with its status.
+func unmetDependency(c Check, results report.ReportChecks) (string, string) {
+	for _, dep := range c.Dependencies() {
+		res, ok := results[dep]
+		if !ok {
+			return dep, "not run"
+		}
+		if res.CheckStatus() != report.StatusPass {
+			return dep, res.CheckStatus()
+		}
+	}
+	return "", ""
+}
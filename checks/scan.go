@@ -0,0 +1,78 @@
+package checks
+
+import (
+	"bytes"
+
+	"golang.org/x/net/html"
+)
+
+// element is a start tag found in an HTML document, with the 1-based line
+// and column where the tag begins.
+type element struct {
+	html.Token
+	Line int
+	Col  int
+
+	// Text is the content of a <style> element, which starts at TextLine
+	// and TextCol.
+	Text     string
+	TextLine int
+	TextCol  int
+}
+
+// attr returns the value of the attribute key and whether it is present.
+func (e element) attr(key string) (string, bool) {
+	for _, a := range e.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// scanElements tokenizes an HTML document and returns its start tags in
+// document order. Unlike html.Parse it keeps source positions, so findings
+// can point at the offending line.
+func scanElements(data []byte) []element {
+	var elements []element
+	z := html.NewTokenizer(bytes.NewReader(data))
+	line, col := 1, 1
+	inStyle := false
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return elements
+		}
+		startLine, startCol := line, col
+		raw := z.Raw()
+		line, col = advance(line, col, string(raw))
+
+		switch {
+		case tt == html.StartTagToken || tt == html.SelfClosingTagToken:
+			el := element{Token: z.Token(), Line: startLine, Col: startCol}
+			elements = append(elements, el)
+			inStyle = tt == html.StartTagToken && el.Data == "style"
+		case tt == html.TextToken && inStyle:
+			last := &elements[len(elements)-1]
+			last.Text = string(raw)
+			last.TextLine, last.TextCol = startLine, startCol
+			inStyle = false
+		default:
+			inStyle = false
+		}
+	}
+}
+
+// advance returns the position after text when text starts at line and col.
+func advance(line, col int, text string) (int, int) {
+	for i := 0; i < len(text); i++ {
+		switch b := text[i]; {
+		case b == '\n':
+			line++
+			col = 1
+		case b&0xC0 != 0x80: // count runes, not UTF-8 continuation bytes
+			col++
+		}
+	}
+	return line, col
+}
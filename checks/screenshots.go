@@ -0,0 +1,190 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+type screenshotsCheck struct{}
+
+func (screenshotsCheck) Name() string           { return "screenshots" }
+func (screenshotsCheck) Dependencies() []string { return nil }
+func (screenshotsCheck) Severity() Severity     { return SeverityError }
+
+func (screenshotsCheck) Run(ctx context.Context, env *Env) report.Result {
+	srv, err := env.Server()
+	if err != nil {
+		return report.ScreenshotsResult{Status: report.StatusFail, Details: err.Error()}
+	}
+	pages, err := env.ScreenshotPages()
+	if err != nil {
+		return report.ScreenshotsResult{Status: report.StatusFail, Details: fmt.Sprintf("Error listing pages: %v", err)}
+	}
+	b, err := env.Browser()
+	if err != nil {
+		return report.ScreenshotsResult{Status: report.StatusSkip, Details: fmt.Sprintf("chromedp not available: %v", err)}
+	}
+	// Capture errors are already recorded as a FAIL in the result
+	result, _ := CaptureScreenshots(ctx, b, srv.URL(), pages, "screenshots", env.Config.Screenshots)
+	return result
+}
+
+// captureMargin is the time a capture is given, on top of the maximum
+// wait for the page to be ready, to load the page and take the screenshot.
+const captureMargin = 30 * time.Second
+
+// captureTimeout bounds a single capture, so a page that is not ready in
+// time is still captured and its pending conditions recorded.
+func captureTimeout(wait config.WaitConfig) time.Duration {
+	return time.Duration(wait.MaxWait) + captureMargin
+}
+
+// ScreenshotFile returns the file name of the screenshot of the page with
+// the given slug in the named viewport, such as "pricing.mobile.png".
+func ScreenshotFile(slug, viewport string) string {
+	return slug + "." + viewport + ".png"
+}
+
+// CaptureScreenshots captures a screenshot of each of pages, paths relative
+// to the site served at siteURL, in each of the viewports of cfg into
+// screenshotsDir, in tabs of b, once the page meets the wait conditions of
+// cfg. Screenshots are named by ScreenshotFile after the page's path, so
+// "/pricing/" in the mobile viewport is pricing.mobile.png. Pages that fail
+// are recorded and fail the result; an error is returned only if nothing
+// was captured.
+func CaptureScreenshots(ctx context.Context, b *Browser, siteURL string, pages []string, screenshotsDir string, cfg config.ScreenshotsConfig) (report.ScreenshotsResult, error) {
+	viewports := cfg.Viewports
+	result := report.ScreenshotsResult{
+		Status:  "PASS",
+		Browser: b.Version,
+	}
+
+	// Create screenshots directory
+	if err := os.MkdirAll(screenshotsDir, 0755); err != nil {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("Failed to create screenshots directory: %v", err)
+		return result, err
+	}
+
+	// Capture every page in every viewport with a worker per tab of b
+	type capture struct{ page, viewport int }
+	slugs := pageSlugs(pages)
+	errs := make([][]error, len(pages))
+	ready := make([][]report.Readiness, len(pages))
+	for i := range pages {
+		errs[i] = make([]error, len(viewports))
+		ready[i] = make([]report.Readiness, len(viewports))
+	}
+	jobs := make(chan capture)
+	var wg sync.WaitGroup
+	for w := 0; w < min(b.Tabs(), len(pages)*len(viewports)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				vp := viewports[c.viewport]
+				url := siteURL + strings.TrimPrefix(pages[c.page], "/")
+				path := filepath.Join(screenshotsDir, ScreenshotFile(slugs[c.page], vp.Name))
+				ready[c.page][c.viewport], errs[c.page][c.viewport] = captureViewport(ctx, b, url, vp, cfg.Wait, path)
+			}
+		}()
+	}
+	for i := range pages {
+		for j := range viewports {
+			jobs <- capture{i, j}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var lastErr error
+	var failed []string
+	captured := 0
+	for i, slug := range slugs {
+		page := report.ScreenshotPage{
+			URL:       pages[i],
+			Viewports: make(map[string]string),
+			Readiness: make(map[string]report.Readiness),
+		}
+		for j, vp := range viewports {
+			if err := errs[i][j]; err != nil {
+				lastErr = err
+				if page.Error == "" {
+					page.Error = fmt.Sprintf("%s: %v", vp.Name, err)
+				}
+				continue
+			}
+			page.Viewports[vp.Name] = filepath.Join(screenshotsDir, ScreenshotFile(slug, vp.Name))
+			page.Readiness[vp.Name] = ready[i][j]
+			captured++
+		}
+		if page.Error != "" {
+			failed = append(failed, page.URL)
+		}
+		result.Pages = append(result.Pages, page)
+	}
+
+	if captured == 0 && lastErr != nil {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("Screenshot failed: %v", lastErr)
+		return result, lastErr
+	}
+	if len(failed) > 0 {
+		result.Status = "FAIL"
+		result.Details = fmt.Sprintf("could not capture %s", strings.Join(failed, ", "))
+	}
+	return result, nil
+}
+
+// captureViewport writes a full-page PNG screenshot of url, taken in a tab
+// of b emulating vp once the page is ready by wait, to path. It returns
+// how the page became ready.
+func captureViewport(ctx context.Context, b *Browser, url string, vp config.Viewport, wait config.WaitConfig, path string) (report.Readiness, error) {
+	ctx, cancel := context.WithTimeout(ctx, captureTimeout(wait))
+	defer cancel()
+
+	ready := newReadiness(wait)
+	var buf []byte
+	err := b.Run(ctx,
+		emulateViewport(vp),
+		ready.listen(),
+		chromedp.Navigate(url),
+		ready.wait(),
+		chromedp.FullScreenshot(&buf, 100),
+	)
+	if err != nil {
+		return ready.Result, err
+	}
+	return ready.Result, os.WriteFile(path, buf, 0644)
+}
+
+// emulateViewport sets the screen size, scale, user agent and touch
+// support of vp.
+func emulateViewport(vp config.Viewport) chromedp.Tasks {
+	scale := vp.DeviceScaleFactor
+	if scale == 0 {
+		scale = 1
+	}
+	opts := []chromedp.EmulateViewportOption{chromedp.EmulateScale(scale)}
+	if vp.Mobile {
+		opts = append(opts, chromedp.EmulateMobile)
+	}
+	if vp.Touch {
+		opts = append(opts, chromedp.EmulateTouch)
+	}
+	tasks := chromedp.Tasks{chromedp.EmulateViewport(int64(vp.Width), int64(vp.Height), opts...)}
+	if vp.UserAgent != "" {
+		tasks = append(tasks, emulation.SetUserAgentOverride(vp.UserAgent))
+	}
+	return tasks
+}
@@ -0,0 +1,190 @@
+package checks
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+// Environment variables that override the tools set in the config file.
+const (
+	EnvLighthouse = "SITE_FORGE_LIGHTHOUSE"
+	EnvNode       = "SITE_FORGE_NODE"
+	EnvChrome     = "CHROME_PATH"
+)
+
+// chromeNames are the executables searched for on PATH, in order.
+var chromeNames = []string{
+	"google-chrome",
+	"google-chrome-stable",
+	"chromium",
+	"chromium-browser",
+	"chrome",
+}
+
+// chromePaths are well-known install locations that are not on PATH.
+var chromePaths = []string{
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+	"/Applications/Chromium.app/Contents/MacOS/Chromium",
+	`C:\Program Files\Google\Chrome\Application\chrome.exe`,
+}
+
+// Tools holds the external programs the browser-based checks run.
+type Tools struct {
+	// Lighthouse is the Lighthouse executable, or "" if none was found.
+	Lighthouse string
+	// Node is the Node.js binary that runs Lighthouse, or "" for the one
+	// on PATH.
+	Node string
+	// Chrome is the Chrome or Chromium executable, or "" if none was
+	// found. It is also launched for Lighthouse so every check uses the
+	// same browser.
+	Chrome string
+	// ChromePort, when set, is the remote debugging port of a running
+	// Chrome that Lighthouse connects to instead of one launched per run.
+	ChromePort int
+}
+
+// ResolveTools returns the tools set in cfg, overridden by the
+// SITE_FORGE_LIGHTHOUSE, SITE_FORGE_NODE and CHROME_PATH environment
+// variables. An unset Lighthouse is looked up on PATH and in node_modules,
+// and an unset Chrome on PATH and in well-known install locations.
+func ResolveTools(cfg config.ToolsConfig) Tools {
+	t := Tools{
+		Lighthouse: cmp.Or(os.Getenv(EnvLighthouse), cfg.Lighthouse),
+		Node:       cmp.Or(os.Getenv(EnvNode), cfg.Node),
+		Chrome:     cmp.Or(os.Getenv(EnvChrome), cfg.Chrome),
+	}
+	if t.Lighthouse == "" {
+		t.Lighthouse = findLighthouse()
+	} else {
+		t.Lighthouse = lookPath(t.Lighthouse)
+	}
+	if t.Node != "" {
+		t.Node = lookPath(t.Node)
+	}
+	if t.Chrome == "" {
+		t.Chrome = findChrome()
+	} else {
+		t.Chrome = lookPath(t.Chrome)
+	}
+	return t
+}
+
+// lookPath returns the absolute path of the executable name, or name
+// itself if it cannot be found, so running it reports the problem.
+func lookPath(name string) string {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return name
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// findLighthouse returns the path of the lighthouse executable on PATH or
+// in node_modules/.bin of the working directory or one of its parents, as
+// npm installs it, or "". Nothing is downloaded: npx could, and without a
+// registry to reach it hangs.
+func findLighthouse() string {
+	if path, err := exec.LookPath("lighthouse"); err == nil {
+		return path
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, "node_modules", ".bin", "lighthouse")
+		if fileExists(path) {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// findChrome returns the path of a Chrome or Chromium executable, or "".
+func findChrome() string {
+	for _, name := range chromeNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	for _, path := range chromePaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// lighthouseCommand returns the command that runs Lighthouse with args:
+// the configured executable, through the configured Node if there is one.
+// The command runs in its own process group and sees the configured Node
+// first on PATH.
+func (t Tools) lighthouseCommand(ctx context.Context, args ...string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if t.Node != "" {
+		cmd = exec.CommandContext(ctx, t.Node, append([]string{t.Lighthouse}, args...)...)
+	} else {
+		cmd = exec.CommandContext(ctx, t.Lighthouse, args...)
+	}
+
+	cmd.Env = os.Environ()
+	if t.Node != "" {
+		path := filepath.Dir(t.Node) + string(os.PathListSeparator) + os.Getenv("PATH")
+		cmd.Env = append(cmd.Env, "PATH="+path)
+	}
+	setProcessGroup(cmd)
+	return cmd
+}
+
+// Versions returns the versions of the tools, leaving out those that
+// could not be run. Each probe gives up after timeout.
+func (t Tools) Versions(ctx context.Context, timeout time.Duration) report.ToolVersions {
+	v := t.runtimeVersions(ctx, timeout)
+	v.Lighthouse, _ = t.lighthouseVersion(ctx, timeout)
+	return v
+}
+
+// runtimeVersions returns the versions of Node and Chrome.
+func (t Tools) runtimeVersions(ctx context.Context, timeout time.Duration) report.ToolVersions {
+	var v report.ToolVersions
+	v.Node, _ = commandVersion(ctx, timeout, cmp.Or(t.Node, "node"), "--version")
+	if t.Chrome != "" {
+		v.Chrome, _ = commandVersion(ctx, timeout, t.Chrome, "--version")
+	}
+	return v
+}
+
+// commandVersion runs name with args and returns the first line of its
+// output, giving up after timeout.
+func commandVersion(ctx context.Context, timeout time.Duration, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		killProcessGroup(cmd)
+	}
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return line, nil
+}
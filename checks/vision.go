@@ -2,6 +2,7 @@ package checks
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -11,11 +12,34 @@ import (
 	"regexp"
 	"strconv"
 
-	"github.com/misty-step/site-forge/internal/report"
+	"github.com/misty-step/site-forge/report"
 )
 
+type visionCheck struct{}
+
+func (visionCheck) Name() string           { return "vision" }
+func (visionCheck) Dependencies() []string { return []string{"screenshots"} }
+func (visionCheck) Severity() Severity     { return SeverityError }
+
+func (visionCheck) Run(ctx context.Context, env *Env) report.Result {
+	cfg := env.Config.Vision
+	if cfg.Baseline == "" {
+		return report.VisionResult{
+			Status:    report.StatusSkip,
+			Details:   "No baseline provided",
+			Threshold: cfg.Threshold,
+		}
+	}
+	result, err := CheckVision(cfg.Baseline, cfg.Model, cfg.Threshold)
+	if err != nil {
+		result.Status = report.StatusSkip
+		result.Details = fmt.Sprintf("vision check failed: %v", err)
+	}
+	return result
+}
+
 // CheckVision compares screenshots with baseline using OpenRouter API
-func CheckVision(baselineDir string, threshold int) (report.VisionResult, error) {
+func CheckVision(baselineDir, model string, threshold int) (report.VisionResult, error) {
 	result := report.VisionResult{
 		Status:    "PASS",
 		Threshold: threshold,
@@ -28,25 +52,25 @@ func CheckVision(baselineDir string, threshold int) (report.VisionResult, error)
 	}
 
 	// Check for baseline screenshots
-	baselineDesktop := filepath.Join(baselineDir, "desktop.png")
-	baselineMobile := filepath.Join(baselineDir, "mobile.png")
+	baselineDesktop := homeScreenshot(baselineDir, "desktop")
+	baselineMobile := homeScreenshot(baselineDir, "mobile")
 
 	if _, err := os.Stat(baselineDesktop); os.IsNotExist(err) {
-		return result, fmt.Errorf("baseline desktop.png not found in %s", baselineDir)
+		return result, fmt.Errorf("baseline %s not found in %s", filepath.Base(baselineDesktop), baselineDir)
 	}
 	if _, err := os.Stat(baselineMobile); os.IsNotExist(err) {
-		return result, fmt.Errorf("baseline mobile.png not found in %s", baselineDir)
+		return result, fmt.Errorf("baseline %s not found in %s", filepath.Base(baselineMobile), baselineDir)
 	}
 
 	// Check for new screenshots
-	newDesktop := "screenshots/desktop.png"
-	newMobile := "screenshots/mobile.png"
+	newDesktop := homeScreenshot("screenshots", "desktop")
+	newMobile := homeScreenshot("screenshots", "mobile")
 
 	if _, err := os.Stat(newDesktop); os.IsNotExist(err) {
-		return result, fmt.Errorf("new desktop.png not found (run screenshots check first)")
+		return result, fmt.Errorf("new %s not found (run screenshots check first)", filepath.Base(newDesktop))
 	}
 	if _, err := os.Stat(newMobile); os.IsNotExist(err) {
-		return result, fmt.Errorf("new mobile.png not found (run screenshots check first)")
+		return result, fmt.Errorf("new %s not found (run screenshots check first)", filepath.Base(newMobile))
 	}
 
 	// Read and encode images
@@ -71,7 +95,7 @@ func CheckVision(baselineDir string, threshold int) (report.VisionResult, error)
 	}
 
 	// Call OpenRouter API with vision model
-	analysis, err := callVisionAPI(apiKey, baselineDesktopBase64, baselineMobileBase64, desktopBase64, mobileBase64)
+	analysis, err := callVisionAPI(apiKey, model, baselineDesktopBase64, baselineMobileBase64, desktopBase64, mobileBase64)
 	if err != nil {
 		return result, fmt.Errorf("vision API call failed: %v", err)
 	}
@@ -88,6 +112,17 @@ func CheckVision(baselineDir string, threshold int) (report.VisionResult, error)
 	return result, nil
 }
 
+// homeScreenshot returns the path of the screenshot of the home page in
+// the named viewport in dir. Baselines captured before every page was
+// screenshotted are named after the viewport alone.
+func homeScreenshot(dir, viewport string) string {
+	path := filepath.Join(dir, ScreenshotFile(pageSlug("/"), viewport))
+	if old := filepath.Join(dir, viewport+".png"); !fileExists(path) && fileExists(old) {
+		return old
+	}
+	return path
+}
+
 func encodeImage(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -124,7 +159,7 @@ type Choice struct {
 	Message Message `json:"message"`
 }
 
-func callVisionAPI(apiKey, baselineDesktop, baselineMobile, newDesktop, newMobile string) (string, error) {
+func callVisionAPI(apiKey, model, baselineDesktop, baselineMobile, newDesktop, newMobile string) (string, error) {
 	prompt := `Compare the original website screenshots (BASELINE) with the redesigned website screenshots (NEW). 
 
 Analyze and score the redesign on a scale of 1-10 for each category:
@@ -144,7 +179,7 @@ OVERALL: X/10
 ANALYSIS: [2-3 sentences of specific feedback on what's better and what could improve]`
 
 	req := OpenRouterRequest{
-		Model: "anthropic/claude-sonnet-4-20250514",
+		Model: model,
 		Messages: []Message{
 			{
 				Role: "user",
@@ -0,0 +1,137 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+type visualCheck struct{}
+
+func (visualCheck) Name() string           { return "visual" }
+func (visualCheck) Dependencies() []string { return []string{"screenshots"} }
+func (visualCheck) Severity() Severity     { return SeverityError }
+
+func (visualCheck) Run(ctx context.Context, env *Env) report.Result {
+	cfg := env.Config.Visual
+	baseline := env.Config.Vision.Baseline
+	if baseline == "" {
+		return report.VisualResult{
+			Status:       report.StatusSkip,
+			MaxDiffRatio: cfg.MaxDiffRatio,
+			Details:      "No baseline provided",
+		}
+	}
+	shots, _ := env.Results["screenshots"].(report.ScreenshotsResult)
+	return CompareScreenshots(shots, baseline, cfg)
+}
+
+// CompareScreenshots compares every capture of shots with the screenshot of
+// the same name in baselineDir, pixel by pixel, and writes an image of the
+// differences of those that differ to the diff directory of cfg. Captures
+// that differ by more than the maximum ratio of cfg fail the result, while
+// those without a baseline are only listed.
+func CompareScreenshots(shots report.ScreenshotsResult, baselineDir string, cfg config.VisualConfig) report.VisualResult {
+	result := report.VisualResult{
+		Status:       report.StatusPass,
+		MaxDiffRatio: cfg.MaxDiffRatio,
+	}
+	if err := os.MkdirAll(cfg.DiffDir, 0755); err != nil {
+		result.Status = report.StatusFail
+		result.Details = fmt.Sprintf("Failed to create diff directory: %v", err)
+		return result
+	}
+
+	compared := 0
+	for _, page := range shots.Pages {
+		viewports := make([]string, 0, len(page.Viewports))
+		for name := range page.Viewports {
+			viewports = append(viewports, name)
+		}
+		sort.Strings(viewports)
+
+		for _, vp := range viewports {
+			c := report.VisualCapture{URL: page.URL, Viewport: vp, Screenshot: page.Viewports[vp]}
+			if base := baselineScreenshot(baselineDir, page.URL, vp, c.Screenshot); fileExists(base) {
+				c.Baseline = base
+				compared++
+				compareCapture(&c, cfg)
+			}
+			if c.Baseline != "" && c.Failed(cfg.MaxDiffRatio) {
+				result.Status = report.StatusFail
+			}
+			result.Captures = append(result.Captures, c)
+		}
+	}
+
+	if compared == 0 {
+		result.Status = report.StatusSkip
+		result.Details = fmt.Sprintf("No baseline screenshots found in %s", baselineDir)
+	}
+	return result
+}
+
+// baselineScreenshot returns the path of the baseline of the screenshot
+// of the page at url in the named viewport: the file of the same name in
+// dir, or the home page screenshot of an older baseline.
+func baselineScreenshot(dir, url, viewport, screenshot string) string {
+	if url == "/" {
+		return homeScreenshot(dir, viewport)
+	}
+	return filepath.Join(dir, filepath.Base(screenshot))
+}
+
+// compareCapture compares the screenshot of c with its baseline and
+// records the difference, writing an image of it if any pixel differs.
+func compareCapture(c *report.VisualCapture, cfg config.VisualConfig) {
+	baseline, err := decodePNG(c.Baseline)
+	if err != nil {
+		c.Error = fmt.Sprintf("read baseline: %v", err)
+		return
+	}
+	shot, err := decodePNG(c.Screenshot)
+	if err != nil {
+		c.Error = fmt.Sprintf("read screenshot: %v", err)
+		return
+	}
+
+	d := diffImages(baseline, shot, cfg.Threshold)
+	c.DiffPixels, c.DiffRatio = d.Mismatched, d.Ratio()
+	if d.Mismatched == 0 {
+		return
+	}
+	path := filepath.Join(cfg.DiffDir, filepath.Base(c.Screenshot))
+	if err := writePNG(path, d.Image); err != nil {
+		c.Error = fmt.Sprintf("write diff: %v", err)
+		return
+	}
+	c.Diff = path
+}
+
+func decodePNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
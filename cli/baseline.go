@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/misty-step/site-forge/checks"
+	"github.com/misty-step/site-forge/report"
+)
+
+func runBaseline(args []string) int {
+	fs := newFlagSet("baseline")
+	out := fs.String("out", "", "Directory to write the baseline screenshots to (default: vision.baseline from config, or ./baseline)")
+	configPath := fs.String("config", "", "Config file (default: site-forge.yaml in the site directory or a parent)")
+	positional, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+
+	absDir, err := siteDir(fs, positional, "./dist")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	cfg, err := loadConfig(*configPath, absDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	outDir := *out
+	if outDir == "" {
+		outDir = cfg.Vision.Baseline
+	}
+	if outDir == "" {
+		outDir = "baseline"
+	}
+
+	env := checks.NewEnv(absDir)
+	env.Config = cfg
+	defer env.Close()
+
+	srv, err := env.Server()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	pages, err := env.ScreenshotPages()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing pages: %v\n", err)
+		return 1
+	}
+
+	b, err := env.Browser()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Capturing baseline of %s into %s\n", absDir, outDir)
+	result, err := checks.CaptureScreenshots(context.Background(), b, srv.URL(), pages, outDir, cfg.Screenshots)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, page := range result.Pages {
+		fmt.Printf("  %s\n", page.URL)
+		for _, vp := range cfg.Screenshots.Viewports {
+			if path, ok := page.Viewports[vp.Name]; ok {
+				fmt.Printf("    %-10s %s\n", vp.Name+":", path)
+			}
+		}
+		if page.Error != "" {
+			fmt.Printf("    error:     %s\n", page.Error)
+		}
+	}
+	for _, line := range result.DetailLines() {
+		fmt.Printf("  %s\n", line)
+	}
+	if result.Status != report.StatusPass {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", result.Details)
+		return 1
+	}
+	if abs, err := filepath.Abs(outDir); err == nil {
+		fmt.Printf("\nCompare against it with: site-forge verify --baseline %s\n", abs)
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/misty-step/site-forge/checks"
+	"github.com/misty-step/site-forge/config"
+)
+
+func runDoctor(args []string) int {
+	fs := newFlagSet("doctor")
+	configPath := fs.String("config", "", "Config file (default: site-forge.yaml in the site directory or a parent)")
+	positional, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	problems := 0
+	line := func(ok bool, warnOnly bool, name, detail string) {
+		switch {
+		case ok:
+			fmt.Printf("  ✅ %s: %s\n", name, detail)
+		case warnOnly:
+			fmt.Printf("  ⚠️  %s: %s\n", name, detail)
+		default:
+			fmt.Printf("  ❌ %s: %s\n", name, detail)
+			problems++
+		}
+	}
+
+	fmt.Println("site-forge doctor:")
+
+	cfg, cfgErr := loadConfig(*configPath, dir)
+	tools := config.Default().Tools
+	if cfgErr == nil {
+		tools = cfg.Tools
+	}
+	resolved := checks.ResolveTools(tools)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	versions := resolved.Versions(ctx, 15*time.Second)
+
+	if versions.Node == "" {
+		line(false, false, "node", "not found (required for Lighthouse, or set "+checks.EnvNode+")")
+	} else {
+		line(true, false, "node", versions.Node)
+	}
+
+	if versions.Lighthouse == "" {
+		line(false, false, "lighthouse", "not installed (run: npm install -g lighthouse, or set "+checks.EnvLighthouse+")")
+	} else {
+		line(true, false, "lighthouse", fmt.Sprintf("%s (%s)", versions.Lighthouse, resolved.Lighthouse))
+	}
+
+	switch {
+	case resolved.Chrome == "":
+		line(false, false, "chrome", "not found (required for Lighthouse and screenshots, or set "+checks.EnvChrome+")")
+	case versions.Chrome == "":
+		line(false, false, "chrome", resolved.Chrome+" does not run")
+	default:
+		line(true, false, "chrome", fmt.Sprintf("%s (%s)", versions.Chrome, resolved.Chrome))
+	}
+
+	if os.Getenv("OPENROUTER_API_KEY") == "" {
+		line(false, true, "OPENROUTER_API_KEY", "not set (vision check will be skipped)")
+	} else {
+		line(true, false, "OPENROUTER_API_KEY", "set")
+	}
+
+	switch {
+	case cfgErr != nil:
+		line(false, false, "config", cfgErr.Error())
+	case cfg.Path == "":
+		line(false, true, "config", "no site-forge.yaml found, using defaults (run: site-forge init)")
+	default:
+		if err := registry.ValidateConfig(cfg); err != nil {
+			line(false, false, "config", fmt.Sprintf("%s: %v", cfg.Path, err))
+		} else {
+			line(true, false, "config", cfg.Path)
+		}
+	}
+
+	if problems > 0 {
+		fmt.Printf("\n%d problem(s) found\n", problems)
+		return 1
+	}
+	fmt.Println("\nEverything looks good")
+	return 0
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/misty-step/site-forge/config"
+)
+
+const starterConfig = `# site-forge configuration. Flags passed to "site-forge verify" override
+# the values in this file.
+
+# URL path the site is deployed under, for sites served from a subpath
+base_path: /
+
+# Pages visited by browser-based checks
+pages:
+  - /
+
+# HTML files that are not checked, relative to the site root
+ignore: []
+#  - "google*.html"
+#  - "fragments/"
+
+# Enable/disable checks or override their severity (error or warning)
+checks: {}
+#  vision:
+#    enabled: false
+#  lighthouse:
+#    severity: warning
+
+build:
+  # Pages that are not complete documents and skip the structure rules
+  exempt: []
+  #  - "google*.html"
+
+lighthouse:
+  # Categories audited and gated on; "pwa" needs Lighthouse 11 or older
+  categories: [performance, accessibility, seo, best-practices]
+  # Audit up to this many pages, adding a sample from sitemap.xml to pages
+  sample: 0
+  # Gate on the worst page ("min"), the "median" or a percentile ("p25")
+  aggregate: min
+  # Audit each page several times and keep the median score
+  runs: 1
+  # Flag scores as unstable when runs differ by more points (0 disables)
+  max_spread: 0
+  concurrency: 1
+  # Audit in the Chrome shared with the screenshots (needs concurrency 1)
+  shared_browser: false
+  # Stop a Lighthouse run after this long, and the installation check
+  timeout: 2m
+  probe_timeout: 30s
+  # Keep the JSON and HTML Lighthouse reports of every page and run here
+  artifacts: ""
+  thresholds:
+    performance: 90
+    accessibility: 90
+    seo: 90
+    best_practices: 90
+    pwa: 90
+  # Lab metric budgets, checked independently of the scores (unset: none)
+  budgets: {}
+  #  lcp: 2.5s
+  #  cls: 0.1
+  #  tbt: 200ms
+  #  total_byte_weight: 1.6MB
+  # Audit with mobile emulation, Lighthouse's desktop preset, or both
+  form_factors: [mobile]
+  # Per form factor: threshold overrides, throttling and screen emulation
+  desktop:
+    thresholds: {}
+  #    performance: 95
+  #  throttling:
+  #    method: simulate
+  #    rtt_ms: 40
+  #    throughput_kbps: 10240
+  #    cpu_slowdown: 1
+  #  screen:
+  #    width: 1350
+  #    height: 940
+  #    device_scale_factor: 1
+
+screenshots:
+  # Pages captured (default: pages above), or every HTML page with all_pages
+  pages: []
+  all_pages: false
+  # Each page is captured in every viewport, as PAGE.VIEWPORT.png
+  viewports:
+    - name: desktop
+      width: 1280
+      height: 900
+    - name: mobile
+      width: 390
+      height: 844
+      user_agent: "` + config.IPhoneUserAgent + `"
+      mobile: true
+      touch: true
+  #  - name: tablet
+  #    width: 768
+  #    height: 1024
+  #    device_scale_factor: 2
+  #    mobile: true
+  #    touch: true
+  # Capture a page once these hold after it loads, or after max_wait
+  wait:
+    conditions: [network_idle, fonts, images]
+    # CSS selector of an element that must be visible
+    selector: ""
+    # JavaScript expression that must be truthy
+    predicate: ""
+    max_wait: 10s
+
+browser:
+  # Tabs of the shared headless Chrome open at once
+  concurrency: 4
+
+# External programs, looked up on PATH when empty. The SITE_FORGE_LIGHTHOUSE,
+# SITE_FORGE_NODE and CHROME_PATH environment variables override these
+tools:
+  # Lighthouse executable; without one, node_modules/.bin is searched too
+  lighthouse: ""
+  node: ""
+  # Chrome used by Lighthouse (as CHROME_PATH) and for screenshots
+  chrome: ""
+
+# Pixel comparison of the screenshots with those of vision.baseline
+visual:
+  # Perceived color difference (0 to 1) above which two pixels differ
+  threshold: 0.1
+  # Largest share of the pixels of a capture that may differ
+  max_diff_ratio: 0.01
+  diff_dir: screenshots/diff
+
+vision:
+  baseline: ""
+  threshold: 7
+  model: ` + config.DefaultVisionModel + `
+`
+
+func runInit(args []string) int {
+	fs := newFlagSet("init")
+	force := fs.Bool("force", false, "Overwrite an existing config file")
+	positional, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+
+	dir, err := siteDir(fs, positional, ".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	path := filepath.Join(dir, config.FileName)
+	if _, err := os.Stat(path); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists (use --force to overwrite)\n", path)
+		return 1
+	}
+	if err := os.WriteFile(path, []byte(starterConfig), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote %s\n", path)
+	return 0
+}
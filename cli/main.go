@@ -0,0 +1,167 @@
+// Package cli implements the site-forge command line. Main runs it with a
+// registry of checks, so a binary can add checks of its own to the
+// built-in ones.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/misty-step/site-forge/checks"
+)
+
+// command is a site-forge subcommand.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) int
+}
+
+var commands []command
+
+// registry holds the checks run by verify and validated by doctor.
+var registry = checks.Default
+
+func init() {
+	commands = []command{
+		{"verify", "verify [flags] [dir]", "Run the quality checks against a built site", runVerify},
+		{"baseline", "baseline [flags] [dir]", "Capture baseline screenshots for visual and vision comparison", runBaseline},
+		{"serve", "serve [flags] [dir]", "Serve a built site locally", runServe},
+		{"report", "report [flags] [file]", "Print the summary of a saved report", runReport},
+		{"doctor", "doctor [flags] [dir]", "Check that the tools used by the checks are installed", runDoctor},
+		{"init", "init [flags] [dir]", "Write a starter site-forge.yaml", runInit},
+		{"help", "help [command]", "Show help for a command", runHelp},
+	}
+}
+
+// Main runs the site-forge command line with args, the arguments after the
+// program name, and returns the exit code. The verify and doctor commands
+// use the checks of reg, usually checks.Default with any checks of the
+// program registered.
+func Main(reg *checks.Registry, args []string) int {
+	registry = reg
+
+	// No command runs verify, on ./dist without arguments or with the
+	// flags given, as in "site-forge --dir ./site"
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		return runVerify(args)
+	}
+	if args[0] == "-h" || args[0] == "--help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		return 2
+	}
+	return cmd.run(args[1:])
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w *os.File) {
+	fmt.Fprintln(w, "site-forge - quality verification harness for static websites")
+	fmt.Fprintln(w, "\nUsage:\n  site-forge <command> [flags] [args]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "\nRun \"site-forge help <command>\" for the flags of a command.")
+}
+
+func runHelp(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return 0
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok || cmd.name == "help" {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n", args[0])
+		return 2
+	}
+	return cmd.run([]string{"-h"})
+}
+
+// newFlagSet returns a flag set whose usage message describes cmd.
+func newFlagSet(name string) *flag.FlagSet {
+	cmd, _ := findCommand(name)
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: site-forge %s\n\n%s\n", cmd.usage, cmd.summary)
+		if hasFlags(fs) {
+			fmt.Fprintln(out, "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	n := 0
+	fs.VisitAll(func(*flag.Flag) { n++ })
+	return n > 0
+}
+
+// parseArgs parses flags that may appear before or after the positional
+// arguments, as in "verify ./dist --baseline ./ref", and returns the
+// positional arguments. It returns an exit code if parsing stopped.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, int, bool) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, 0, false
+			}
+			return nil, 2, false
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, 0, true
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// siteDir returns the absolute path of the directory argument of a command,
+// or def if none was given.
+func siteDir(fs *flag.FlagSet, positional []string, def string) (string, error) {
+	if len(positional) > 1 {
+		return "", fmt.Errorf("%s takes at most one directory, got %d arguments", fs.Name(), len(positional))
+	}
+	dir := def
+	if len(positional) == 1 {
+		dir = positional[0]
+	}
+	if dir == "" {
+		return "", fmt.Errorf("a directory is required")
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving path: %v", err)
+	}
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return absDir, nil
+}
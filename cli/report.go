@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/misty-step/site-forge/report"
+)
+
+func runReport(args []string) int {
+	fs := newFlagSet("report")
+	positional, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+	if len(positional) > 1 {
+		fmt.Fprintf(os.Stderr, "Error: report takes at most one file, got %d arguments\n", len(positional))
+		return 2
+	}
+
+	path := "forge-report.json"
+	if len(positional) == 1 {
+		path = positional[0]
+	}
+
+	r, err := report.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading report: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Report for %s (%s)\n\n", r.Directory, r.Timestamp)
+	fmt.Println(r.FormatSummary())
+	if r.Overall != report.StatusPass {
+		return 1
+	}
+	return 0
+}
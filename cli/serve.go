@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/misty-step/site-forge/internal/server"
+)
+
+func runServe(args []string) int {
+	fs := newFlagSet("serve")
+	host := fs.String("host", "localhost", "Host to listen on")
+	port := fs.Int("port", 8080, "Port to listen on")
+	configPath := fs.String("config", "", "Config file (default: site-forge.yaml in the site directory or a parent)")
+	positional, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+
+	absDir, err := siteDir(fs, positional, "./dist")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	cfg, err := loadConfig(*configPath, absDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	srv, err := server.Start(absDir, server.Options{
+		Addr:     fmt.Sprintf("%s:%d", *host, *port),
+		BasePath: cfg.BasePath,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		return 1
+	}
+	defer srv.Close()
+
+	fmt.Printf("Serving %s at %s (Ctrl+C to stop)\n", absDir, srv.URL())
+	<-ctx.Done()
+	return 0
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/misty-step/site-forge/checks"
+	"github.com/misty-step/site-forge/config"
+	"github.com/misty-step/site-forge/report"
+)
+
+func runVerify(args []string) int {
+	fs := newFlagSet("verify")
+	dir := fs.String("dir", "", "Directory to verify (alternative to the positional argument, default ./dist)")
+	baseline := fs.String("baseline", "", "Baseline directory for visual and vision comparison")
+	threshold := fs.Int("threshold", 7, "Vision score threshold (1-10)")
+	lighthousePerf := fs.Int("lighthouse-perf", 90, "Lighthouse performance threshold")
+	lighthouseA11y := fs.Int("lighthouse-a11y", 90, "Lighthouse accessibility threshold")
+	lighthouseSEO := fs.Int("lighthouse-seo", 90, "Lighthouse SEO threshold")
+	lighthouseBP := fs.Int("lighthouse-bp", 90, "Lighthouse best-practices threshold")
+	lighthousePWA := fs.Int("lighthouse-pwa", 90, "Lighthouse PWA threshold (only gated when the pwa category is audited)")
+	lighthouseArtifacts := fs.String("lighthouse-artifacts", "", "Directory to keep the JSON and HTML Lighthouse reports of every page and run in")
+	lighthouseCategories := fs.String("lighthouse-categories", "", "Comma-separated Lighthouse categories to audit (default: performance,accessibility,seo,best-practices)")
+	lighthouseFormFactors := fs.String("lighthouse-form-factors", "", "Comma-separated form factors to audit: mobile, desktop (default: mobile)")
+	configPath := fs.String("config", "", "Config file (default: site-forge.yaml in the site directory or a parent)")
+	failFast := fs.Bool("fail-fast", false, "Stop at the first failing check instead of running them all")
+	reportPath := fs.String("report", "forge-report.json", "Path of the JSON report")
+	positional, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+
+	def := *dir
+	if def == "" {
+		def = "./dist"
+	}
+	absDir, err := siteDir(fs, positional, def)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	cfg, err := loadConfig(*configPath, absDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	// Flags given on the command line override the config file
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "baseline":
+			cfg.Vision.Baseline = *baseline
+		case "threshold":
+			cfg.Vision.Threshold = *threshold
+		case "lighthouse-perf":
+			cfg.Lighthouse.Thresholds.Performance = *lighthousePerf
+		case "lighthouse-a11y":
+			cfg.Lighthouse.Thresholds.Accessibility = *lighthouseA11y
+		case "lighthouse-seo":
+			cfg.Lighthouse.Thresholds.SEO = *lighthouseSEO
+		case "lighthouse-bp":
+			cfg.Lighthouse.Thresholds.BestPractices = *lighthouseBP
+		case "lighthouse-pwa":
+			cfg.Lighthouse.Thresholds.PWA = *lighthousePWA
+		case "lighthouse-artifacts":
+			cfg.Lighthouse.Artifacts = *lighthouseArtifacts
+		case "lighthouse-categories":
+			cfg.Lighthouse.Categories = splitList(*lighthouseCategories)
+		case "lighthouse-form-factors":
+			cfg.Lighthouse.FormFactors = splitList(*lighthouseFormFactors)
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := registry.ValidateConfig(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in %s: %v\n", cfg.Path, err)
+		return 1
+	}
+
+	fmt.Printf("Verifying site in: %s\n", absDir)
+	if cfg.Path != "" {
+		fmt.Printf("Using config: %s\n", cfg.Path)
+	}
+
+	env := checks.NewEnv(absDir)
+	env.Config = cfg
+	defer env.Close()
+
+	r := report.NewReport(absDir)
+	runner := &checks.Runner{
+		Registry: registry,
+		FailFast: *failFast,
+		OnStart: func(c checks.Check, index, total int) {
+			fmt.Printf("[%d/%d] Running %s check... ", index+1, total, strings.ToUpper(c.Name()))
+		},
+		OnFinish: func(c checks.Check, result report.Result) {
+			fmt.Printf("%s (%s)\n", result.CheckStatus(), result.Summary())
+		},
+	}
+
+	// Ctrl-C stops the running check, killing Lighthouse and Chrome,
+	// and still writes the report
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Println()
+	if err := runner.Run(ctx, env, r); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("\n" + r.FormatSummary())
+	writeReport(r, *reportPath)
+
+	if r.Overall != report.StatusPass {
+		return 1
+	}
+	fmt.Println("\n✅ All checks passed!")
+	return 0
+}
+
+// loadConfig loads the config file at path or, if path is empty, the one
+// discovered from dir. Without a config file the defaults are used.
+func loadConfig(path, dir string) (*config.Config, error) {
+	if path == "" {
+		found, err := config.Find(dir)
+		if err != nil {
+			return nil, err
+		}
+		if found == "" {
+			return config.Default(), nil
+		}
+		path = found
+	}
+	return config.Load(path)
+}
+
+func writeReport(r *report.Report, path string) {
+	r.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+	}
+}
+
+// splitList splits a comma- or space-separated flag value.
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,741 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the config file looked up by Find.
+const FileName = "site-forge.yaml"
+
+// DefaultVisionModel is the OpenRouter model used by the vision check.
+const DefaultVisionModel = "anthropic/claude-sonnet-4-20250514"
+
+// Config is the project configuration read from site-forge.yaml.
+type Config struct {
+	// Path is the file the config was loaded from, empty for defaults.
+	Path string `yaml:"-"`
+
+	// BasePath is the URL path the site is deployed under, such as
+	// "/docs/" for a site served from example.com/docs/.
+	BasePath string `yaml:"base_path"`
+	// Pages lists the URL paths of the pages browser-based checks visit.
+	Pages []string `yaml:"pages"`
+	// Ignore lists glob patterns, relative to the site root, of HTML files
+	// that are not checked.
+	Ignore []string `yaml:"ignore"`
+	// Checks enables, disables or overrides the severity of checks by name.
+	Checks map[string]CheckConfig `yaml:"checks"`
+
+	Build       BuildConfig       `yaml:"build"`
+	Lighthouse  LighthouseConfig  `yaml:"lighthouse"`
+	Screenshots ScreenshotsConfig `yaml:"screenshots"`
+	Browser     BrowserConfig     `yaml:"browser"`
+	Visual      VisualConfig      `yaml:"visual"`
+	Vision      VisionConfig      `yaml:"vision"`
+	Tools       ToolsConfig       `yaml:"tools"`
+}
+
+// CheckConfig overrides the defaults of a single check.
+type CheckConfig struct {
+	Enabled  *bool  `yaml:"enabled"`
+	Severity string `yaml:"severity"`
+}
+
+type BuildConfig struct {
+	// Exempt lists patterns, in the same form as Ignore, of pages that are
+	// not required to be complete documents, such as fragments or search
+	// console verification files.
+	Exempt []string `yaml:"exempt"`
+}
+
+type LighthouseConfig struct {
+	// Categories lists the ids of the Lighthouse categories audited and
+	// gated on.
+	Categories []string `yaml:"categories"`
+	// Sample, when positive, adds pages picked from sitemap.xml, or from
+	// the site's HTML files if there is none, to Pages until up to Sample
+	// pages are audited.
+	Sample int `yaml:"sample"`
+	// Aggregate combines the scores of the audited pages before they are
+	// compared with the thresholds: "min" for the worst page, "median", or
+	// a percentile such as "p25".
+	Aggregate string `yaml:"aggregate"`
+	// Runs is the number of audits of each page. The median score of each
+	// category is kept.
+	Runs int `yaml:"runs"`
+	// MaxSpread, when positive, flags a page as unstable if a category
+	// score varies by more than this many points between its runs.
+	MaxSpread int `yaml:"max_spread"`
+	// Artifacts, when set, is the directory the JSON and HTML Lighthouse
+	// reports of every page and run are kept in.
+	Artifacts string `yaml:"artifacts"`
+	// Concurrency is the number of pages audited at once. Parallel audits
+	// compete for CPU, which lowers performance scores.
+	Concurrency int `yaml:"concurrency"`
+	// SharedBrowser runs the audits in the browser shared with the other
+	// checks instead of a Chrome launched for each run. Lighthouse runs in
+	// one browser interfere, so it requires a Concurrency of 1.
+	SharedBrowser bool `yaml:"shared_browser"`
+	// Timeout stops a Lighthouse run that has not finished after this
+	// long, and ProbeTimeout the check that Lighthouse is installed.
+	Timeout      Duration `yaml:"timeout"`
+	ProbeTimeout Duration `yaml:"probe_timeout"`
+
+	// FormFactors lists the form factors audited: "mobile", Lighthouse's
+	// default emulation, and "desktop", its desktop preset.
+	FormFactors []string `yaml:"form_factors"`
+
+	Thresholds LighthouseThresholds `yaml:"thresholds"`
+	Budgets    LighthouseBudgets    `yaml:"budgets"`
+
+	// Mobile and Desktop adjust the audits of each form factor.
+	Mobile  FormFactorConfig `yaml:"mobile"`
+	Desktop FormFactorConfig `yaml:"desktop"`
+}
+
+// FormFactorConfig holds the settings of one form factor.
+type FormFactorConfig struct {
+	// Thresholds overrides lighthouse.thresholds by category, such as
+	// {performance: 95}.
+	Thresholds map[string]int   `yaml:"thresholds"`
+	Throttling ThrottlingConfig `yaml:"throttling"`
+	Screen     ScreenConfig     `yaml:"screen"`
+}
+
+// ThrottlingConfig overrides Lighthouse's network and CPU throttling. Zero
+// values keep the defaults of the form factor.
+type ThrottlingConfig struct {
+	// Method is "simulate", "devtools" or "provided" (no throttling).
+	Method         string  `yaml:"method"`
+	RTT            float64 `yaml:"rtt_ms"`
+	ThroughputKbps float64 `yaml:"throughput_kbps"`
+	CPUSlowdown    float64 `yaml:"cpu_slowdown"`
+	// RequestLatency and the download and upload throughputs apply to the
+	// "devtools" method.
+	RequestLatency         float64 `yaml:"request_latency_ms"`
+	DownloadThroughputKbps float64 `yaml:"download_throughput_kbps"`
+	UploadThroughputKbps   float64 `yaml:"upload_throughput_kbps"`
+}
+
+// ScreenConfig overrides Lighthouse's screen emulation. Zero values keep
+// the defaults of the form factor.
+type ScreenConfig struct {
+	Width             int     `yaml:"width"`
+	Height            int     `yaml:"height"`
+	DeviceScaleFactor float64 `yaml:"device_scale_factor"`
+	// Disabled turns screen emulation off, for instance when the viewport
+	// is set through Chrome flags.
+	Disabled bool `yaml:"disabled"`
+}
+
+// FormFactor returns the settings of the named form factor.
+func (c LighthouseConfig) FormFactor(name string) FormFactorConfig {
+	if name == "desktop" {
+		return c.Desktop
+	}
+	return c.Mobile
+}
+
+// ThresholdsFor returns the thresholds of the named form factor.
+func (c LighthouseConfig) ThresholdsFor(name string) LighthouseThresholds {
+	t := c.Thresholds
+	for key, v := range c.FormFactor(name).Thresholds {
+		if field := t.field(key); field != nil {
+			*field = v
+		}
+	}
+	return t
+}
+
+// LighthouseBudgets caps lab metrics independently of the category scores.
+// Zero values set no budget.
+type LighthouseBudgets struct {
+	LCP             Duration `yaml:"lcp"`
+	CLS             float64  `yaml:"cls"`
+	TBT             Duration `yaml:"tbt"`
+	FCP             Duration `yaml:"fcp"`
+	SpeedIndex      Duration `yaml:"speed_index"`
+	TTI             Duration `yaml:"tti"`
+	TotalByteWeight ByteSize `yaml:"total_byte_weight"`
+}
+
+// Limits returns the configured budgets by metric key, with times in
+// milliseconds and sizes in bytes.
+func (b LighthouseBudgets) Limits() map[string]float64 {
+	limits := make(map[string]float64)
+	set := func(key string, v float64) {
+		if v != 0 {
+			limits[key] = v
+		}
+	}
+	set("lcp", b.LCP.Milliseconds())
+	set("cls", b.CLS)
+	set("tbt", b.TBT.Milliseconds())
+	set("fcp", b.FCP.Milliseconds())
+	set("speed_index", b.SpeedIndex.Milliseconds())
+	set("tti", b.TTI.Milliseconds())
+	set("total_byte_weight", float64(b.TotalByteWeight))
+	return limits
+}
+
+// Duration is a time.Duration written as "2.5s" or "200ms", or as a number
+// of milliseconds.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	if node.Tag == "!!int" || node.Tag == "!!float" {
+		ms, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return err
+		}
+		*d = Duration(ms * float64(time.Millisecond))
+		return nil
+	}
+	v, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: invalid duration %q", node.Line, node.Value)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Milliseconds returns d in milliseconds.
+func (d Duration) Milliseconds() float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// ByteSize is a size written as a number of bytes or with a unit, such as
+// "500KB", "1.5MB" or "2MiB".
+type ByteSize int64
+
+var byteUnits = []struct {
+	suffix string
+	size   float64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1e3},
+	{"MB", 1e6},
+	{"GB", 1e9},
+	{"B", 1},
+}
+
+func (b *ByteSize) UnmarshalYAML(node *yaml.Node) error {
+	value := strings.TrimSpace(node.Value)
+	unit := 1.0
+	for _, u := range byteUnits {
+		if n, ok := strings.CutSuffix(value, u.suffix); ok {
+			value, unit = strings.TrimSpace(n), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("line %d: invalid size %q", node.Line, node.Value)
+	}
+	*b = ByteSize(n * unit)
+	return nil
+}
+
+// Percentile returns the percentile Aggregate selects: 0 for "min", 50 for
+// "median" and NN for "pNN".
+func (c LighthouseConfig) Percentile() (int, bool) {
+	switch c.Aggregate {
+	case "min":
+		return 0, true
+	case "median":
+		return 50, true
+	}
+	if rest, ok := strings.CutPrefix(c.Aggregate, "p"); ok {
+		if p, err := strconv.Atoi(rest); err == nil && p >= 0 && p <= 100 {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+type LighthouseThresholds struct {
+	Performance   int `yaml:"performance"`
+	Accessibility int `yaml:"accessibility"`
+	SEO           int `yaml:"seo"`
+	BestPractices int `yaml:"best_practices"`
+	PWA           int `yaml:"pwa"`
+}
+
+// field returns the threshold with the given yaml key.
+func (t *LighthouseThresholds) field(key string) *int {
+	switch key {
+	case "performance":
+		return &t.Performance
+	case "accessibility":
+		return &t.Accessibility
+	case "seo":
+		return &t.SEO
+	case "best_practices":
+		return &t.BestPractices
+	case "pwa":
+		return &t.PWA
+	}
+	return nil
+}
+
+// LighthouseCategories lists the ids of the categories that can be set in
+// lighthouse.categories. The "pwa" category was removed in Lighthouse 12.
+var LighthouseCategories = []string{"performance", "accessibility", "seo", "best-practices", "pwa"}
+
+type ScreenshotsConfig struct {
+	// Pages lists the URL paths of the pages captured, overriding the
+	// top-level pages, and AllPages captures every HTML page of the site.
+	Pages    []string `yaml:"pages"`
+	AllPages bool     `yaml:"all_pages"`
+	// Viewports lists the viewports every screenshot is taken in, each
+	// written to NAME.png. Setting it replaces the default desktop and
+	// mobile viewports.
+	Viewports []Viewport `yaml:"viewports"`
+	// Wait sets when a page is ready to be captured.
+	Wait WaitConfig `yaml:"wait"`
+}
+
+// WaitConfig lists the conditions a page must meet, once loaded, before it
+// is captured. Pages that do not meet them within MaxWait are captured as
+// they are, and the conditions left are recorded in the report.
+type WaitConfig struct {
+	// Conditions are awaited in order: "network_idle" for no network
+	// activity for 500ms, "fonts" for document.fonts.ready and "images"
+	// for every img complete, lazy ones included.
+	Conditions []string `yaml:"conditions"`
+	// Selector, when set, must match a visible element.
+	Selector string `yaml:"selector"`
+	// Predicate, when set, is a JavaScript expression that must be truthy,
+	// or a promise that resolves to a truthy value.
+	Predicate string `yaml:"predicate"`
+	// MaxWait bounds the wait for all the conditions.
+	MaxWait Duration `yaml:"max_wait"`
+}
+
+// WaitConditions lists the values of screenshots.wait.conditions.
+var WaitConditions = []string{"network_idle", "fonts", "images"}
+
+// Viewport describes the emulated screen and device of a screenshot.
+type Viewport struct {
+	// Name identifies the viewport in file names and the report.
+	Name   string `yaml:"name"`
+	Width  int    `yaml:"width"`
+	Height int    `yaml:"height"`
+	// DeviceScaleFactor is the ratio of device pixels to CSS pixels; zero
+	// means 1.
+	DeviceScaleFactor float64 `yaml:"device_scale_factor"`
+	// UserAgent overrides the browser's user agent when set.
+	UserAgent string `yaml:"user_agent"`
+	// Mobile emulates a mobile device, which honors the page's meta
+	// viewport, and Touch enables touch events.
+	Mobile bool `yaml:"mobile"`
+	Touch  bool `yaml:"touch"`
+}
+
+// IPhoneUserAgent is the user agent of the default mobile viewport.
+const IPhoneUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1"
+
+// BrowserConfig sets up the headless Chrome shared by the browser-based
+// checks.
+type BrowserConfig struct {
+	// Concurrency is the number of tabs open at once.
+	Concurrency int `yaml:"concurrency"`
+}
+
+// ToolsConfig locates the external programs used by the browser-based
+// checks. Empty values are looked up on PATH, and the SITE_FORGE_LIGHTHOUSE,
+// SITE_FORGE_NODE and CHROME_PATH environment variables take precedence.
+type ToolsConfig struct {
+	// Lighthouse is the Lighthouse executable or CLI script. Without one,
+	// it is looked up on PATH and in node_modules/.bin.
+	Lighthouse string `yaml:"lighthouse"`
+	// Node is the Node.js binary that runs Lighthouse.
+	Node string `yaml:"node"`
+	// Chrome is the Chrome or Chromium executable used for Lighthouse and
+	// screenshots.
+	Chrome string `yaml:"chrome"`
+}
+
+// VisualConfig sets up the pixel comparison of the screenshots with the
+// baseline of vision.baseline.
+type VisualConfig struct {
+	// Threshold is the perceived color difference, from 0 to 1, above which
+	// two pixels differ.
+	Threshold float64 `yaml:"threshold"`
+	// MaxDiffRatio is the largest share of the pixels of a capture, from 0
+	// to 1, that may differ from its baseline.
+	MaxDiffRatio float64 `yaml:"max_diff_ratio"`
+	// DiffDir is the directory the images highlighting the differences are
+	// written to, named after the captures.
+	DiffDir string `yaml:"diff_dir"`
+}
+
+type VisionConfig struct {
+	Baseline  string `yaml:"baseline"`
+	Threshold int    `yaml:"threshold"`
+	Model     string `yaml:"model"`
+}
+
+// Default returns the configuration used when no file is present.
+func Default() *Config {
+	return &Config{
+		Pages: []string{"/"},
+		Lighthouse: LighthouseConfig{
+			Categories:   []string{"performance", "accessibility", "seo", "best-practices"},
+			FormFactors:  []string{"mobile"},
+			Aggregate:    "min",
+			Runs:         1,
+			Concurrency:  1,
+			Timeout:      Duration(2 * time.Minute),
+			ProbeTimeout: Duration(30 * time.Second),
+			Thresholds: LighthouseThresholds{
+				Performance:   90,
+				Accessibility: 90,
+				SEO:           90,
+				BestPractices: 90,
+				PWA:           90,
+			},
+		},
+		Screenshots: ScreenshotsConfig{
+			Viewports: []Viewport{
+				{Name: "desktop", Width: 1280, Height: 900},
+				{Name: "mobile", Width: 390, Height: 844, UserAgent: IPhoneUserAgent, Mobile: true, Touch: true},
+			},
+			Wait: WaitConfig{
+				Conditions: []string{"network_idle", "fonts", "images"},
+				MaxWait:    Duration(10 * time.Second),
+			},
+		},
+		Browser: BrowserConfig{
+			Concurrency: 4,
+		},
+		Visual: VisualConfig{
+			Threshold:    0.1,
+			MaxDiffRatio: 0.01,
+			DiffDir:      "screenshots/diff",
+		},
+		Vision: VisionConfig{
+			Threshold: 7,
+			Model:     DefaultVisionModel,
+		},
+	}
+}
+
+// Find looks for FileName in dir and its parents, stopping at the first
+// directory that contains a .git entry. It returns "" if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		candidate := filepath.Join(dir, FileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates the config file at path. Values missing from the
+// file keep their defaults.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	cfg.Path = path
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// ValidationError lists every problem found in a config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks the values of cfg against the schema.
+func (c *Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.BasePath != "" && !strings.HasPrefix(c.BasePath, "/") {
+		addf("base_path: must start with \"/\", got %q", c.BasePath)
+	}
+	for i, p := range c.Pages {
+		if !strings.HasPrefix(p, "/") {
+			addf("pages[%d]: must start with \"/\", got %q", i, p)
+		}
+	}
+	for i, pattern := range c.Ignore {
+		if _, err := path.Match(pattern, ""); err != nil {
+			addf("ignore[%d]: invalid pattern %q", i, pattern)
+		}
+	}
+	for i, pattern := range c.Build.Exempt {
+		if _, err := path.Match(pattern, ""); err != nil {
+			addf("build.exempt[%d]: invalid pattern %q", i, pattern)
+		}
+	}
+	for name, check := range c.Checks {
+		switch check.Severity {
+		case "", "error", "warning":
+		default:
+			addf("checks.%s.severity: must be \"error\" or \"warning\", got %q", name, check.Severity)
+		}
+	}
+
+	if c.Lighthouse.Sample < 0 {
+		addf("lighthouse.sample: must not be negative, got %d", c.Lighthouse.Sample)
+	}
+	if _, ok := c.Lighthouse.Percentile(); !ok {
+		addf("lighthouse.aggregate: must be \"min\", \"median\" or a percentile such as \"p25\", got %q", c.Lighthouse.Aggregate)
+	}
+	if c.Lighthouse.Runs < 1 {
+		addf("lighthouse.runs: must be at least 1, got %d", c.Lighthouse.Runs)
+	}
+	if c.Lighthouse.MaxSpread < 0 || c.Lighthouse.MaxSpread > 100 {
+		addf("lighthouse.max_spread: must be between 0 and 100, got %d", c.Lighthouse.MaxSpread)
+	}
+	if c.Lighthouse.Concurrency < 1 {
+		addf("lighthouse.concurrency: must be at least 1, got %d", c.Lighthouse.Concurrency)
+	}
+	if c.Lighthouse.SharedBrowser && c.Lighthouse.Concurrency > 1 {
+		addf("lighthouse.shared_browser: audits in one browser must run one at a time, got concurrency %d", c.Lighthouse.Concurrency)
+	}
+	if c.Lighthouse.Timeout <= 0 {
+		addf("lighthouse.timeout: must be positive")
+	}
+	if c.Lighthouse.ProbeTimeout <= 0 {
+		addf("lighthouse.probe_timeout: must be positive")
+	}
+	for key, limit := range c.Lighthouse.Budgets.Limits() {
+		if limit < 0 {
+			addf("lighthouse.budgets.%s: must not be negative", key)
+		}
+	}
+	if len(c.Lighthouse.Categories) == 0 {
+		addf("lighthouse.categories: must list at least one category")
+	}
+	seen := make(map[string]bool)
+	for i, cat := range c.Lighthouse.Categories {
+		switch {
+		case !slices.Contains(LighthouseCategories, cat):
+			addf("lighthouse.categories[%d]: unknown category %q (known: %s)", i, cat, strings.Join(LighthouseCategories, ", "))
+		case seen[cat]:
+			addf("lighthouse.categories[%d]: duplicate category %q", i, cat)
+		}
+		seen[cat] = true
+	}
+	t := c.Lighthouse.Thresholds
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"performance", t.Performance},
+		{"accessibility", t.Accessibility},
+		{"seo", t.SEO},
+		{"best_practices", t.BestPractices},
+		{"pwa", t.PWA},
+	} {
+		if f.value < 0 || f.value > 100 {
+			addf("lighthouse.thresholds.%s: must be between 0 and 100, got %d", f.name, f.value)
+		}
+	}
+
+	if len(c.Lighthouse.FormFactors) == 0 {
+		addf("lighthouse.form_factors: must list at least one form factor")
+	}
+	for i, ff := range c.Lighthouse.FormFactors {
+		if ff != "mobile" && ff != "desktop" {
+			addf("lighthouse.form_factors[%d]: must be \"mobile\" or \"desktop\", got %q", i, ff)
+		} else if slices.Index(c.Lighthouse.FormFactors, ff) != i {
+			addf("lighthouse.form_factors[%d]: duplicate form factor %q", i, ff)
+		}
+	}
+	for _, ff := range []string{"mobile", "desktop"} {
+		f := c.Lighthouse.FormFactor(ff)
+		for _, key := range slices.Sorted(maps.Keys(f.Thresholds)) {
+			var t LighthouseThresholds
+			switch v := f.Thresholds[key]; {
+			case t.field(key) == nil:
+				addf("lighthouse.%s.thresholds.%s: unknown category", ff, key)
+			case v < 0 || v > 100:
+				addf("lighthouse.%s.thresholds.%s: must be between 0 and 100, got %d", ff, key, v)
+			}
+		}
+		switch f.Throttling.Method {
+		case "", "simulate", "devtools", "provided":
+		default:
+			addf("lighthouse.%s.throttling.method: must be \"simulate\", \"devtools\" or \"provided\", got %q", ff, f.Throttling.Method)
+		}
+		th := f.Throttling
+		if th.RTT < 0 || th.ThroughputKbps < 0 || th.CPUSlowdown < 0 || th.RequestLatency < 0 || th.DownloadThroughputKbps < 0 || th.UploadThroughputKbps < 0 {
+			addf("lighthouse.%s.throttling: values must not be negative", ff)
+		}
+		if f.Screen.Width < 0 || f.Screen.Height < 0 || f.Screen.DeviceScaleFactor < 0 {
+			addf("lighthouse.%s.screen: values must not be negative", ff)
+		}
+	}
+
+	for i, p := range c.Screenshots.Pages {
+		if !strings.HasPrefix(p, "/") {
+			addf("screenshots.pages[%d]: must start with \"/\", got %q", i, p)
+		}
+	}
+	if len(c.Screenshots.Viewports) == 0 {
+		addf("screenshots.viewports: must list at least one viewport")
+	}
+	for i, v := range c.Screenshots.Viewports {
+		switch {
+		case !validViewportName(v.Name):
+			addf("screenshots.viewports[%d].name: must be letters, digits, \"-\" or \"_\", got %q", i, v.Name)
+		case slices.IndexFunc(c.Screenshots.Viewports, func(o Viewport) bool { return o.Name == v.Name }) != i:
+			addf("screenshots.viewports[%d].name: duplicate viewport %q", i, v.Name)
+		}
+		if v.Width <= 0 || v.Height <= 0 {
+			addf("screenshots.viewports[%d]: width and height must be positive, got %dx%d", i, v.Width, v.Height)
+		}
+		if v.DeviceScaleFactor < 0 {
+			addf("screenshots.viewports[%d].device_scale_factor: must not be negative", i)
+		}
+	}
+
+	for i, cond := range c.Screenshots.Wait.Conditions {
+		switch {
+		case !slices.Contains(WaitConditions, cond):
+			addf("screenshots.wait.conditions[%d]: unknown condition %q (known: %s)", i, cond, strings.Join(WaitConditions, ", "))
+		case slices.Index(c.Screenshots.Wait.Conditions, cond) != i:
+			addf("screenshots.wait.conditions[%d]: duplicate condition %q", i, cond)
+		}
+	}
+	if c.Screenshots.Wait.MaxWait <= 0 {
+		addf("screenshots.wait.max_wait: must be positive")
+	}
+
+	if c.Browser.Concurrency < 1 {
+		addf("browser.concurrency: must be at least 1, got %d", c.Browser.Concurrency)
+	}
+
+	if c.Visual.Threshold < 0 || c.Visual.Threshold > 1 {
+		addf("visual.threshold: must be between 0 and 1, got %g", c.Visual.Threshold)
+	}
+	if c.Visual.MaxDiffRatio < 0 || c.Visual.MaxDiffRatio > 1 {
+		addf("visual.max_diff_ratio: must be between 0 and 1, got %g", c.Visual.MaxDiffRatio)
+	}
+	if c.Visual.DiffDir == "" {
+		addf("visual.diff_dir: must not be empty")
+	}
+
+	if c.Vision.Threshold < 1 || c.Vision.Threshold > 10 {
+		addf("vision.threshold: must be between 1 and 10, got %d", c.Vision.Threshold)
+	}
+	if c.Vision.Model == "" {
+		addf("vision.model: must not be empty")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validViewportName reports whether name can be used as a file name.
+func validViewportName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Enabled reports whether the named check should run.
+func (c *Config) Enabled(name string) bool {
+	if check, ok := c.Checks[name]; ok && check.Enabled != nil {
+		return *check.Enabled
+	}
+	return true
+}
+
+// Ignored reports whether the file at rel, a slash-separated path relative
+// to the site root, matches one of the ignore patterns.
+func (c *Config) Ignored(rel string) bool {
+	return matchAny(c.Ignore, rel)
+}
+
+// BuildExempt reports whether the page at rel is exempt from the build
+// check's document structure rules.
+func (c *Config) BuildExempt(rel string) bool {
+	return matchAny(c.Build.Exempt, rel)
+}
+
+// matchAny reports whether rel matches one of patterns. Patterns without a
+// slash match the file name in any directory, and patterns ending in "/"
+// or "/**" match everything below that directory.
+func matchAny(patterns []string, rel string) bool {
+	rel = strings.TrimPrefix(filepath.ToSlash(rel), "/")
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(pattern, "/")
+		if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+			pattern = dir + "/"
+		}
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(rel, pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, FileName)
+	content := `pages: ["/", "/about/"]
+ignore: ["google*.html", "fragments/"]
+checks:
+  lighthouse:
+    severity: warning
+  vision:
+    enabled: false
+lighthouse:
+  thresholds:
+    performance: 80
+  budgets:
+    lcp: 2.5s
+    cls: 0.1
+    tbt: 300
+    total_byte_weight: 1.5MB
+  form_factors: [mobile, desktop]
+  timeout: 90s
+  desktop:
+    thresholds:
+      performance: 95
+    throttling:
+      cpu_slowdown: 2
+screenshots:
+  viewports:
+    - name: tablet
+      width: 768
+      height: 1024
+      device_scale_factor: 2
+      touch: true
+    - name: wide
+      width: 1920
+      height: 1080
+  wait:
+    conditions: [fonts]
+    selector: "#app"
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Lighthouse.Thresholds.Performance != 80 {
+		t.Errorf("Expected performance threshold 80, got %d", cfg.Lighthouse.Thresholds.Performance)
+	}
+	if len(cfg.Lighthouse.Categories) != 4 || cfg.Lighthouse.Thresholds.BestPractices != 90 {
+		t.Errorf("Expected best-practices audited by default, got %v", cfg.Lighthouse.Categories)
+	}
+	if cfg.Lighthouse.Thresholds.SEO != 90 {
+		t.Errorf("Expected default SEO threshold 90, got %d", cfg.Lighthouse.Thresholds.SEO)
+	}
+	if desktop := cfg.Lighthouse.ThresholdsFor("desktop"); desktop.Performance != 95 || desktop.SEO != 90 {
+		t.Errorf("Expected desktop thresholds to override performance only, got %+v", desktop)
+	}
+	if mobile := cfg.Lighthouse.ThresholdsFor("mobile"); mobile.Performance != 80 {
+		t.Errorf("Expected mobile thresholds from lighthouse.thresholds, got %+v", mobile)
+	}
+	if cfg.Lighthouse.FormFactor("desktop").Throttling.CPUSlowdown != 2 {
+		t.Errorf("Expected desktop CPU slowdown 2, got %+v", cfg.Lighthouse.Desktop.Throttling)
+	}
+	if cfg.Lighthouse.Timeout != Duration(90*time.Second) || cfg.Lighthouse.ProbeTimeout != Duration(30*time.Second) {
+		t.Errorf("Unexpected timeouts %v, %v", time.Duration(cfg.Lighthouse.Timeout), time.Duration(cfg.Lighthouse.ProbeTimeout))
+	}
+	limits := cfg.Lighthouse.Budgets.Limits()
+	if len(limits) != 4 || limits["lcp"] != 2500 || limits["cls"] != 0.1 || limits["tbt"] != 300 || limits["total_byte_weight"] != 1.5e6 {
+		t.Errorf("Unexpected budgets %v", limits)
+	}
+	if vps := cfg.Screenshots.Viewports; len(vps) != 2 || vps[0].Name != "tablet" || vps[0].DeviceScaleFactor != 2 || !vps[0].Touch || vps[1].Width != 1920 {
+		t.Errorf("Expected the tablet and wide viewports to replace the defaults, got %+v", vps)
+	}
+	if w := cfg.Screenshots.Wait; len(w.Conditions) != 1 || w.Selector != "#app" || w.MaxWait != Duration(10*time.Second) {
+		t.Errorf("Expected fonts and a selector awaited for 10s, got %+v", w)
+	}
+	if cfg.Vision.Model != DefaultVisionModel {
+		t.Errorf("Expected default vision model, got %q", cfg.Vision.Model)
+	}
+	if cfg.Enabled("vision") || !cfg.Enabled("assets") {
+		t.Errorf("Expected vision disabled and assets enabled")
+	}
+	if len(cfg.Pages) != 2 {
+		t.Errorf("Expected 2 pages, got %v", cfg.Pages)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, FileName)
+
+	os.WriteFile(path, []byte("lighthouse:\n  treshold: 80\n"), 0644)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "treshold") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+
+	content := `pages: ["about"]
+checks:
+  build:
+    severity: fatal
+lighthouse:
+  categories: [performance, speed]
+  aggregate: p250
+  budgets:
+    lcp: -1s
+  thresholds:
+    performance: 120
+  form_factors: [mobile, tablet]
+  concurrency: 2
+  shared_browser: true
+  timeout: 0s
+  desktop:
+    thresholds:
+      speed: 90
+    throttling:
+      method: fast
+screenshots:
+  pages: [pricing]
+  viewports:
+    - name: "tablet/portrait"
+      width: 768
+      height: 1024
+    - name: wide
+      width: 0
+      height: 1080
+    - name: wide
+      width: 1920
+      height: 1080
+  wait:
+    conditions: [fonts, load, fonts]
+    max_wait: 0s
+browser:
+  concurrency: 0
+visual:
+  max_diff_ratio: 5
+vision:
+  threshold: 0
+`
+	os.WriteFile(path, []byte(content), 0644)
+	_, err = Load(path)
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	for _, want := range []string{"pages[0]", "checks.build.severity", "lighthouse.categories[1]", "lighthouse.aggregate", "lighthouse.budgets.lcp", "lighthouse.thresholds.performance", "lighthouse.form_factors[1]", "lighthouse.shared_browser", "lighthouse.timeout", "lighthouse.desktop.thresholds.speed", "lighthouse.desktop.throttling.method", "screenshots.pages[0]", "screenshots.viewports[0].name", "screenshots.viewports[1]: width", "screenshots.viewports[2].name: duplicate", "screenshots.wait.conditions[1]: unknown", "screenshots.wait.conditions[2]: duplicate", "screenshots.wait.max_wait", "browser.concurrency", "visual.max_diff_ratio", "vision.threshold"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got: %v", want, err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "site-forge-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "dist"), 0755)
+
+	path, err := Find(filepath.Join(tmpDir, "dist"))
+	if err != nil || path != "" {
+		t.Errorf("Expected no config, got %q (%v)", path, err)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, FileName), []byte{}, 0644)
+	path, err = Find(filepath.Join(tmpDir, "dist"))
+	if err != nil || path != filepath.Join(tmpDir, FileName) {
+		t.Errorf("Expected config in parent, got %q (%v)", path, err)
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	cfg := Default()
+	cfg.Ignore = []string{"google*.html", "fragments/", "/drafts/**", "blog/tmp.html"}
+
+	tests := []struct {
+		path    string
+		ignored bool
+	}{
+		{"google1234.html", true},
+		{"sub/google1234.html", true},
+		{"fragments/nav.html", true},
+		{"drafts/a/b.html", true},
+		{"blog/tmp.html", true},
+		{"other/blog/tmp.html", false},
+		{"index.html", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.Ignored(tt.path); got != tt.ignored {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.ignored)
+		}
+	}
+}
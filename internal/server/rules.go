@@ -0,0 +1,188 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// headerRule adds headers to responses for paths matching a pattern, as
+// declared in a _headers file:
+//
+//	/assets/*
+//	  Cache-Control: public, max-age=31536000, immutable
+type headerRule struct {
+	pattern string
+	values  [][2]string
+}
+
+func (r headerRule) matches(sitePath string) bool {
+	_, ok := matchPattern(r.pattern, sitePath)
+	return ok
+}
+
+// redirectRule is a line of a _redirects file:
+//
+//	/old/*  /new/:splat  301
+//	/app/*  /app/index.html  200
+//	/docs   /docs/v2  302!
+type redirectRule struct {
+	from   string
+	to     string
+	status int
+	// force applies the rule even when a file exists at the path.
+	force bool
+}
+
+func (r redirectRule) statusOr(def int) int {
+	if r.status >= 300 && r.status < 400 {
+		return r.status
+	}
+	return def
+}
+
+// match returns the target of the rule for sitePath, with placeholders and
+// the splat substituted.
+func (r redirectRule) match(sitePath string) (string, bool) {
+	params, ok := matchPattern(r.from, sitePath)
+	if !ok {
+		return "", false
+	}
+	target := r.to
+	// Substitute longer names first so :id does not clobber :identifier
+	for {
+		longest := ""
+		for name := range params {
+			if strings.Contains(target, ":"+name) && len(name) > len(longest) {
+				longest = name
+			}
+		}
+		if longest == "" {
+			break
+		}
+		target = strings.ReplaceAll(target, ":"+longest, params[longest])
+	}
+	return target, true
+}
+
+// matchPattern matches a site path against a pattern made of literal
+// segments, ":name" placeholders matching one segment, and a final "*"
+// matching the rest of the path (available as "splat"). Trailing slashes are
+// not significant, as on Netlify.
+func matchPattern(pattern, sitePath string) (map[string]string, bool) {
+	pat := splitPath(pattern)
+	segs := splitPath(sitePath)
+	params := make(map[string]string)
+
+	for i, p := range pat {
+		if p == "*" && i == len(pat)-1 {
+			params["splat"] = strings.Join(segs[i:], "/")
+			return params, true
+		}
+		if i >= len(segs) {
+			return nil, false
+		}
+		switch {
+		case strings.HasPrefix(p, ":"):
+			params[p[1:]] = segs[i]
+		case p != segs[i]:
+			return nil, false
+		}
+	}
+	if len(pat) != len(segs) {
+		return nil, false
+	}
+	return params, true
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// loadHeaders parses a _headers file. A missing file has no rules.
+func loadHeaders(file string) ([]headerRule, error) {
+	var rules []headerRule
+	err := readRuleFile(file, func(lineNo int, line string, indented bool) error {
+		if !indented {
+			rules = append(rules, headerRule{pattern: strings.TrimSpace(line)})
+			return nil
+		}
+		if len(rules) == 0 {
+			return fmt.Errorf("header without a path")
+		}
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("expected \"Name: value\", got %q", strings.TrimSpace(line))
+		}
+		last := &rules[len(rules)-1]
+		last.values = append(last.values, [2]string{strings.TrimSpace(name), strings.TrimSpace(value)})
+		return nil
+	})
+	return rules, err
+}
+
+// loadRedirects parses a _redirects file. A missing file has no rules.
+// Rules with query parameter or country conditions are not supported and
+// are ignored.
+func loadRedirects(file string) ([]redirectRule, error) {
+	var rules []redirectRule
+	err := readRuleFile(file, func(lineNo int, line string, indented bool) error {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return fmt.Errorf("expected \"from to [status]\", got %q", strings.TrimSpace(line))
+		}
+		for _, f := range fields[1:] {
+			if strings.Contains(f, "=") {
+				return nil
+			}
+		}
+
+		rule := redirectRule{from: fields[0], to: fields[1], status: 301}
+		if len(fields) > 2 {
+			code, force := strings.CutSuffix(fields[2], "!")
+			status, err := strconv.Atoi(code)
+			if err != nil {
+				return fmt.Errorf("invalid status %q", fields[2])
+			}
+			rule.status = status
+			rule.force = force
+		}
+		rules = append(rules, rule)
+		return nil
+	})
+	return rules, err
+}
+
+// readRuleFile calls fn for every non-blank, non-comment line of file.
+func readRuleFile(file string, fn func(lineNo int, line string, indented bool) error) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indented := line[0] == ' ' || line[0] == '\t'
+		if err := fn(lineNo, line, indented); err != nil {
+			return fmt.Errorf("%s:%d: %v", file, lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,343 @@
+// Package server serves a built static site the way production static hosts
+// do, so browser-based checks see what visitors will see.
+//
+// On top of plain file serving it supports clean URLs (/about serves
+// about.html), trailing-slash redirects for directories, a custom 404.html,
+// precompressed .br/.gz variants and on-the-fly gzip, and Netlify/Cloudflare
+// Pages style _headers and _redirects files.
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Addr is the address to listen on. Defaults to "localhost:0", which
+	// picks a free port.
+	Addr string
+	// BasePath is the URL path the site is served under, such as "/docs/".
+	BasePath string
+}
+
+// Server is a static file server for one site directory.
+type Server struct {
+	dir       string
+	basePath  string
+	headers   []headerRule
+	redirects []redirectRule
+
+	listener net.Listener
+	http     *http.Server
+}
+
+// New returns a Server for dir, reading its _headers and _redirects files.
+// Use it as an http.Handler, or call Start to listen on a port.
+func New(dir string, opts Options) (*Server, error) {
+	s := &Server{
+		dir:      dir,
+		basePath: normalizeBasePath(opts.BasePath),
+	}
+
+	var err error
+	if s.headers, err = loadHeaders(filepath.Join(dir, "_headers")); err != nil {
+		return nil, err
+	}
+	if s.redirects, err = loadRedirects(filepath.Join(dir, "_redirects")); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Start serves dir on opts.Addr. The listener is bound before Start
+// returns, so there is no window in which another process can take the
+// port, and Start waits until the server answers requests.
+func Start(dir string, opts Options) (*Server, error) {
+	s, err := New(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := opts.Addr
+	if addr == "" {
+		addr = "localhost:0"
+	}
+	s.listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %v", addr, err)
+	}
+
+	s.http = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := s.http.Serve(s.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		}
+	}()
+
+	if err := s.waitReady(5 * time.Second); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// waitReady polls the server until it answers a request.
+func (s *Server) waitReady(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.URL(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("server not ready after %s: %v", timeout, err)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// URL returns the URL of the site root, including the base path.
+func (s *Server) URL() string {
+	return "http://" + s.Addr() + s.basePath
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	if s.http == nil {
+		return nil
+	}
+	return s.http.Close()
+}
+
+func normalizeBasePath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return "/"
+	}
+	return "/" + p + "/"
+}
+
+// ServeHTTP serves a request the way a static host would.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	urlPath := r.URL.Path
+	if urlPath+"/" == s.basePath {
+		s.redirect(w, r, s.basePath, http.StatusMovedPermanently)
+		return
+	}
+	if !strings.HasPrefix(urlPath, s.basePath) {
+		s.notFound(w, r)
+		return
+	}
+	sitePath := path.Clean("/" + strings.TrimPrefix(urlPath, s.basePath))
+	if strings.HasSuffix(urlPath, "/") && sitePath != "/" {
+		sitePath += "/"
+	}
+
+	status := http.StatusOK
+	for _, rule := range s.redirects {
+		target, ok := rule.match(sitePath)
+		if !ok {
+			continue
+		}
+		// Like Netlify, rules only apply when no file shadows them, but a
+		// later forced rule still can
+		if !rule.force {
+			if _, found := s.lookup(sitePath); found {
+				continue
+			}
+		}
+		if isExternal(target) {
+			http.Redirect(w, r, target, rule.statusOr(http.StatusFound))
+			return
+		}
+		switch {
+		case rule.status >= 300 && rule.status < 400:
+			s.redirect(w, r, s.siteURL(target), rule.status)
+			return
+		case rule.status == http.StatusOK:
+			sitePath = target
+		default:
+			sitePath = target
+			status = rule.status
+		}
+		break
+	}
+
+	file, found := s.lookup(sitePath)
+	if !found {
+		// A directory without a trailing slash redirects to the slash form
+		if !strings.HasSuffix(sitePath, "/") && isFile(filepath.Join(s.fsPath(sitePath), "index.html")) {
+			s.redirect(w, r, s.siteURL(sitePath+"/"), http.StatusMovedPermanently)
+			return
+		}
+		s.notFound(w, r)
+		return
+	}
+	s.serveFile(w, r, sitePath, file, status)
+}
+
+// lookup returns the file served for a site path, following the directory
+// index and clean URL conventions.
+func (s *Server) lookup(sitePath string) (string, bool) {
+	base := path.Base(sitePath)
+	if base == "_headers" || base == "_redirects" {
+		return "", false
+	}
+
+	fsPath := s.fsPath(sitePath)
+	if strings.HasSuffix(sitePath, "/") {
+		index := filepath.Join(fsPath, "index.html")
+		return index, isFile(index)
+	}
+	if isFile(fsPath) {
+		return fsPath, true
+	}
+	if isFile(fsPath + ".html") {
+		return fsPath + ".html", true
+	}
+	return "", false
+}
+
+func (s *Server) fsPath(sitePath string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+sitePath)))
+}
+
+// siteURL maps a site path onto a URL path under the base path.
+func (s *Server) siteURL(sitePath string) string {
+	return s.basePath + strings.TrimPrefix(sitePath, "/")
+}
+
+// redirect redirects to target, keeping the query string of the request.
+func (s *Server) redirect(w http.ResponseWriter, r *http.Request, target string, code int) {
+	if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+		target += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, target, code)
+}
+
+// notFound serves the site's 404.html with a 404 status, or a plain 404.
+func (s *Server) notFound(w http.ResponseWriter, r *http.Request) {
+	page := filepath.Join(s.dir, "404.html")
+	if isFile(page) {
+		s.serveFile(w, r, "/404.html", page, http.StatusNotFound)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// serveFile writes file with its headers, negotiating a precompressed or
+// gzip-compressed body when the client accepts one.
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, sitePath, file string, status int) {
+	h := w.Header()
+	for _, rule := range s.headers {
+		if rule.matches(sitePath) {
+			for _, kv := range rule.values {
+				h.Add(kv[0], kv[1])
+			}
+		}
+	}
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", contentType(file))
+	}
+
+	accept := r.Header.Get("Accept-Encoding")
+	body := file
+	for _, enc := range []struct{ name, ext string }{{"br", ".br"}, {"gzip", ".gz"}} {
+		if acceptsEncoding(accept, enc.name) && isFile(file+enc.ext) {
+			body = file + enc.ext
+			h.Set("Content-Encoding", enc.name)
+			break
+		}
+	}
+	if compressible(h.Get("Content-Type")) || body != file {
+		h.Add("Vary", "Accept-Encoding")
+	}
+
+	f, err := os.Open(body)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if body == file && compressible(h.Get("Content-Type")) && acceptsEncoding(accept, "gzip") {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := io.Copy(gz, f); err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		gz.Close()
+		h.Set("Content-Encoding", "gzip")
+		h.Set("Content-Length", fmt.Sprint(buf.Len()))
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			w.Write(buf.Bytes())
+		}
+		return
+	}
+
+	if status != http.StatusOK {
+		h.Set("Content-Length", fmt.Sprint(info.Size()))
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			io.Copy(w, f)
+		}
+		return
+	}
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+func isFile(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && !info.IsDir()
+}
+
+func isExternal(target string) bool {
+	return strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+}
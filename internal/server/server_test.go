@@ -0,0 +1,209 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSite(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func get(t *testing.T, h http.Handler, target string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeHTTP(t *testing.T) {
+	dir := writeSite(t, map[string]string{
+		"index.html":      "<h1>Home</h1>",
+		"about.html":      "<h1>About</h1>",
+		"blog/index.html": "<h1>Blog</h1>",
+		"404.html":        "<h1>Not here</h1>",
+		"app.js":          "console.log(1)",
+		"img/logo.svg":    "<svg></svg>",
+		"legacy.html":     "<h1>Legacy</h1>",
+		"_headers":        "# security\n/*\n  X-Frame-Options: DENY\n/img/*\n  Cache-Control: max-age=60\n",
+		"_redirects":      "/old-blog/*  /blog/:splat  301\n/posts/:id  /blog/  302\n/spa/*  /index.html  200\n/gone  /404.html  410\n/legacy  /blog/  301\n/legacy  /archive/  301!\n",
+	})
+	s, err := New(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target   string
+		status   int
+		body     string
+		location string
+	}{
+		{"/", 200, "Home", ""},
+		{"/about", 200, "About", ""},
+		{"/about.html", 200, "About", ""},
+		{"/blog/", 200, "Blog", ""},
+		{"/blog", 301, "", "/blog/"},
+		{"/blog?page=2", 301, "", "/blog/?page=2"},
+		{"/missing", 404, "Not here", ""},
+		{"/_headers", 404, "Not here", ""},
+		{"/old-blog/", 301, "", "/blog/"},
+		{"/posts/42", 302, "", "/blog/"},
+		{"/spa/settings", 200, "Home", ""},
+		{"/gone", 410, "Not here", ""},
+		// legacy.html shadows the first rule but not the forced one
+		{"/legacy", 301, "", "/archive/"},
+	}
+	for _, tt := range tests {
+		rec := get(t, s, tt.target)
+		if rec.Code != tt.status {
+			t.Errorf("GET %s: status %d, want %d", tt.target, rec.Code, tt.status)
+		}
+		if !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("GET %s: body %q, want it to contain %q", tt.target, rec.Body.String(), tt.body)
+		}
+		if got := rec.Header().Get("Location"); got != tt.location {
+			t.Errorf("GET %s: Location %q, want %q", tt.target, got, tt.location)
+		}
+	}
+
+	rec := get(t, s, "/img/logo.svg")
+	if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("Content-Type = %q, want image/svg+xml", ct)
+	}
+	if got := rec.Header().Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("X-Frame-Options = %q, want DENY", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "max-age=60" {
+		t.Errorf("Cache-Control = %q, want max-age=60", got)
+	}
+	if ct := get(t, s, "/app.js").Header().Get("Content-Type"); ct != "text/javascript; charset=utf-8" {
+		t.Errorf("Content-Type of app.js = %q", ct)
+	}
+}
+
+func TestServeHTTPCompression(t *testing.T) {
+	dir := writeSite(t, map[string]string{
+		"index.html":   strings.Repeat("<p>hello</p>", 100),
+		"style.css":    "body{}",
+		"style.css.br": "brotli bytes",
+		"style.css.gz": "gzip bytes",
+		"photo.png":    "png bytes",
+		"scripts/a.js": "a()",
+	})
+	s, err := New(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := get(t, s, "/style.css", "Accept-Encoding", "gzip, br")
+	if rec.Header().Get("Content-Encoding") != "br" || rec.Body.String() != "brotli bytes" {
+		t.Errorf("br: encoding %q, body %q", rec.Header().Get("Content-Encoding"), rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/css; charset=utf-8" {
+		t.Errorf("precompressed Content-Type = %q, want the type of the original", ct)
+	}
+
+	rec = get(t, s, "/style.css", "Accept-Encoding", "gzip, br;q=0")
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Body.String() != "gzip bytes" {
+		t.Errorf("gz: encoding %q, body %q", rec.Header().Get("Content-Encoding"), rec.Body.String())
+	}
+
+	rec = get(t, s, "/", "Accept-Encoding", "gzip")
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("on-the-fly gzip: encoding %q", rec.Header().Get("Content-Encoding"))
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(zr)
+	if !strings.HasPrefix(string(body), "<p>hello</p>") {
+		t.Errorf("decompressed body = %.20q", body)
+	}
+
+	rec = get(t, s, "/photo.png", "Accept-Encoding", "gzip")
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("png should not be compressed, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	rec = get(t, s, "/")
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("no Accept-Encoding should get identity, got %q", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestServeHTTPBasePath(t *testing.T) {
+	dir := writeSite(t, map[string]string{
+		"index.html": "Home",
+		"404.html":   "Not here",
+	})
+	s, err := New(dir, Options{BasePath: "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(t, s, "/docs/"); rec.Code != 200 || rec.Body.String() != "Home" {
+		t.Errorf("GET /docs/: %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := get(t, s, "/docs"); rec.Code != 301 || rec.Header().Get("Location") != "/docs/" {
+		t.Errorf("GET /docs: %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := get(t, s, "/"); rec.Code != 404 {
+		t.Errorf("GET /: %d, want 404", rec.Code)
+	}
+}
+
+func TestLoadRulesInvalid(t *testing.T) {
+	dir := writeSite(t, map[string]string{"_headers": "  X-Frame-Options: DENY\n"})
+	if _, err := New(dir, Options{}); err == nil || !strings.Contains(err.Error(), "_headers:1") {
+		t.Errorf("New() error = %v, want a _headers:1 error", err)
+	}
+	dir = writeSite(t, map[string]string{"_redirects": "/a /b abc\n"})
+	if _, err := New(dir, Options{}); err == nil || !strings.Contains(err.Error(), "_redirects:1") {
+		t.Errorf("New() error = %v, want a _redirects:1 error", err)
+	}
+}
+
+func TestStart(t *testing.T) {
+	dir := writeSite(t, map[string]string{"index.html": "Home"})
+	s, err := Start(dir, Options{BasePath: "/site/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !strings.HasSuffix(s.URL(), "/site/") {
+		t.Errorf("URL() = %q, want the base path", s.URL())
+	}
+	// Start returns once the server is ready, so no retry is needed
+	resp, err := http.Get(s.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || string(body) != "Home" {
+		t.Errorf("GET %s: %d %q", s.URL(), resp.StatusCode, body)
+	}
+}
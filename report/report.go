@@ -0,0 +1,978 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Check statuses shared by every result type.
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusSkip = "SKIP"
+	// StatusBlocked marks a check that did not run because one of its
+	// dependencies did not pass.
+	StatusBlocked = "BLOCKED"
+)
+
+// Result is implemented by the result type of every check.
+type Result interface {
+	// CheckStatus returns the PASS/FAIL/SKIP/BLOCKED status of the check.
+	CheckStatus() string
+	// Summary returns the one-line description used by FormatSummary.
+	Summary() string
+}
+
+// Detailer is implemented by results that list more than their summary,
+// such as the findings to fix. FormatSummary prints the lines below the
+// summary of the check.
+type Detailer interface {
+	DetailLines() []string
+}
+
+type Report struct {
+	Timestamp string       `json:"timestamp"`
+	Directory string       `json:"directory"`
+	Overall   string       `json:"overall"`
+	Checks    ReportChecks `json:"checks"`
+
+	// order records the sequence in which results were added so the
+	// summary follows the pipeline rather than map iteration order.
+	order []string
+}
+
+// ReportChecks maps a check name to its result.
+type ReportChecks map[string]Result
+
+func NewReport(dir string) *Report {
+	return &Report{
+		Directory: dir,
+		Overall:   StatusFail,
+		Checks:    ReportChecks{},
+	}
+}
+
+// Add records the result of the named check.
+func (r *Report) Add(name string, result Result) {
+	if _, ok := r.Checks[name]; !ok {
+		r.order = append(r.order, name)
+	}
+	r.Checks[name] = result
+}
+
+// Names returns the check names in the order their results were added.
+// Results set directly on Checks follow in alphabetical order.
+func (r *Report) Names() []string {
+	names := append([]string(nil), r.order...)
+	var extra []string
+	for name := range r.Checks {
+		if !contains(r.order, name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// reportJSON is the serialized form of a Report. Checks is written by hand
+// so the results keep the order the checks ran in.
+type reportJSON struct {
+	Timestamp string          `json:"timestamp"`
+	Directory string          `json:"directory"`
+	Overall   string          `json:"overall"`
+	Checks    json.RawMessage `json:"checks"`
+}
+
+func (r *Report) MarshalJSON() ([]byte, error) {
+	var checks bytes.Buffer
+	checks.WriteByte('{')
+	for i, name := range r.Names() {
+		if i > 0 {
+			checks.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.Checks[name])
+		if err != nil {
+			return nil, fmt.Errorf("check %s: %v", name, err)
+		}
+		checks.Write(key)
+		checks.WriteByte(':')
+		checks.Write(value)
+	}
+	checks.WriteByte('}')
+
+	return json.Marshal(reportJSON{
+		Timestamp: r.Timestamp,
+		Directory: r.Directory,
+		Overall:   r.Overall,
+		Checks:    checks.Bytes(),
+	})
+}
+
+func (r *Report) UnmarshalJSON(data []byte) error {
+	var raw reportJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.Timestamp = raw.Timestamp
+	r.Directory = raw.Directory
+	r.Overall = raw.Overall
+	r.Checks = ReportChecks{}
+	r.order = nil
+	if len(raw.Checks) == 0 || string(raw.Checks) == "null" {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw.Checks))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		result, err := decodeResult(name, value)
+		if err != nil {
+			return fmt.Errorf("check %s: %v", name, err)
+		}
+		r.Add(name, result)
+	}
+	return nil
+}
+
+// decodeResult decodes the result of the named check into its result type.
+// Results of checks this package does not know are decoded as BasicResult.
+func decodeResult(name string, data []byte) (Result, error) {
+	var result Result
+	var err error
+	switch name {
+	case "assets":
+		var v AssetsResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	case "build":
+		var v BuildResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	case "links":
+		var v LinksResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	case "lighthouse":
+		var v LighthouseResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	case "screenshots":
+		var v ScreenshotsResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	case "visual":
+		var v VisualResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	case "vision":
+		var v VisionResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	default:
+		var v BasicResult
+		err = json.Unmarshal(data, &v)
+		result = v
+	}
+	return result, err
+}
+
+// Load reads a report written by site-forge verify.
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return r, nil
+}
+
+func (r *Report) FormatSummary() string {
+	summary := "site-forge verify results:\n"
+
+	for _, name := range r.Names() {
+		result := r.Checks[name]
+		label := strings.ToUpper(name)
+		switch result.CheckStatus() {
+		case StatusPass:
+			summary += fmt.Sprintf("  ✅ %s: %s\n", label, result.Summary())
+		case StatusSkip:
+			summary += fmt.Sprintf("  ⚠️  %s: SKIP - %s\n", label, result.Summary())
+		case StatusBlocked:
+			summary += fmt.Sprintf("  ⛔ %s: BLOCKED - %s\n", label, result.Summary())
+		default:
+			summary += fmt.Sprintf("  ❌ %s: %s\n", label, result.Summary())
+		}
+		if d, ok := result.(Detailer); ok {
+			for _, line := range d.DetailLines() {
+				summary += "       " + line + "\n"
+			}
+		}
+	}
+
+	summary += fmt.Sprintf("\nOVERALL: %s\n", r.Overall)
+	if r.Overall == StatusPass {
+		summary += "✅"
+	} else {
+		summary += "❌"
+	}
+
+	return summary
+}
+
+// BasicResult is the result of a check that only reports a status, such as
+// a check that was not run or a house check with nothing else to record.
+type BasicResult struct {
+	Status  string `json:"status"`
+	Details string `json:"details,omitempty"`
+}
+
+func (r BasicResult) CheckStatus() string { return r.Status }
+
+func (r BasicResult) Summary() string {
+	if r.Status == StatusFail {
+		return "FAIL - " + r.Details
+	}
+	return r.Details
+}
+
+type AssetsResult struct {
+	Status string `json:"status"`
+	// Total counts every reference; Unique counts distinct local assets.
+	Total   int            `json:"total"`
+	Unique  int            `json:"unique"`
+	Missing []AssetFinding `json:"missing,omitempty"`
+	Details string         `json:"details"`
+}
+
+// AssetFinding is a reference to an asset that could not be served.
+type AssetFinding struct {
+	// URL is the reference as written in the source.
+	URL string `json:"url"`
+	// Path is the resolved file, relative to the site root.
+	Path string `json:"path"`
+	// Source is the page or stylesheet containing the reference, relative
+	// to the site root.
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// Element and Attribute name where the URL was found, such as "img"
+	// and "srcset", or "url()" and "@import" for CSS.
+	Element   string `json:"element,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// Location returns the finding's position as "source:line:column".
+func (f AssetFinding) Location() string {
+	return fmt.Sprintf("%s:%d:%d", f.Source, f.Line, f.Column)
+}
+
+func (r AssetsResult) CheckStatus() string { return r.Status }
+
+func (r AssetsResult) Summary() string {
+	switch {
+	case r.Status == StatusPass:
+		return fmt.Sprintf("%d/%d assets verified (%d unique)", r.Total, r.Total, r.Unique)
+	case r.Status == StatusFail && len(r.Missing) > 0:
+		first := r.Missing[0]
+		return fmt.Sprintf("FAIL - Missing %d assets (first: %s %s, %s)", len(r.Missing), first.Location(), first.URL, first.Reason)
+	case r.Status == StatusFail:
+		return "FAIL - " + r.Details
+	}
+	return r.Details
+}
+
+type BuildResult struct {
+	Status   string         `json:"status"`
+	Pages    int            `json:"pages"`
+	Findings []PageFindings `json:"findings,omitempty"`
+	Details  string         `json:"details"`
+}
+
+// PageFindings lists the problems found in one HTML page.
+type PageFindings struct {
+	Page     string   `json:"page"`
+	Problems []string `json:"problems"`
+}
+
+func (r BuildResult) CheckStatus() string { return r.Status }
+
+func (r BuildResult) Summary() string {
+	if r.Status == StatusFail {
+		return "FAIL - " + r.Details
+	}
+	return r.Details
+}
+
+type LinksResult struct {
+	Status  string       `json:"status"`
+	Total   int          `json:"total"`
+	Broken  []BrokenLink `json:"broken,omitempty"`
+	Details string       `json:"details"`
+}
+
+// BrokenLink is an internal hyperlink whose target does not exist.
+type BrokenLink struct {
+	Page   string `json:"page"`
+	Line   int    `json:"line"`
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+func (r LinksResult) CheckStatus() string { return r.Status }
+
+func (r LinksResult) Summary() string {
+	switch {
+	case r.Status == StatusPass:
+		return fmt.Sprintf("%d/%d internal links verified", r.Total, r.Total)
+	case r.Status == StatusFail && len(r.Broken) > 0:
+		return fmt.Sprintf("FAIL - %d broken links (first: %s:%d %s)",
+			len(r.Broken), r.Broken[0].Page, r.Broken[0].Line, r.Broken[0].URL)
+	case r.Status == StatusFail:
+		return "FAIL - " + r.Details
+	}
+	return r.Details
+}
+
+type LighthouseResult struct {
+	Status string `json:"status"`
+	// FormFactor is "mobile" or "desktop". Reports written before form
+	// factors were configurable have none and audited mobile.
+	FormFactor string `json:"form_factor,omitempty"`
+	// FormFactors holds one result per form factor when several were
+	// audited; the status then combines theirs and the fields below are
+	// unset.
+	FormFactors []LighthouseResult `json:"form_factors,omitempty"`
+	// Categories lists the ids of the audited categories. Reports written
+	// before categories were configurable have none and audited
+	// performance, accessibility and SEO.
+	Categories []string `json:"categories,omitempty"`
+	// LighthouseScores combines the scores of every audited page as
+	// selected by Aggregate, and the metrics the same way with the worst
+	// page having the highest values. They are what is gated on.
+	LighthouseScores
+	Aggregate string `json:"aggregate,omitempty"`
+	// Budgets holds the outcome of each configured metric budget.
+	Budgets []MetricBudget `json:"budgets,omitempty"`
+	// Audits lists the failing and low-scoring audits of all pages, worst
+	// first.
+	Audits []LighthouseAudit `json:"audits,omitempty"`
+	// Artifacts is the directory the full Lighthouse reports were written
+	// to, if any.
+	Artifacts string `json:"artifacts,omitempty"`
+	// Pages holds the scores of each audited page.
+	Pages []LighthousePage `json:"pages,omitempty"`
+	// Unstable is set when the scores of a page varied between runs by
+	// more than the configured bound.
+	Unstable   bool       `json:"unstable,omitempty"`
+	Thresholds Thresholds `json:"thresholds"`
+	// ErrorKind classifies the error that stopped the audits, if any.
+	ErrorKind string `json:"error_kind,omitempty"`
+	// Tools records the versions of the programs that ran the audits.
+	Tools   *ToolVersions `json:"tools,omitempty"`
+	Details string        `json:"details,omitempty"`
+}
+
+// ToolVersions holds the versions of the external programs a check ran,
+// empty for those that could not be found.
+type ToolVersions struct {
+	Lighthouse string `json:"lighthouse,omitempty"`
+	Node       string `json:"node,omitempty"`
+	Chrome     string `json:"chrome,omitempty"`
+}
+
+// Kinds of errors that stop a Lighthouse audit.
+const (
+	// LighthouseNotInstalled means Lighthouse could not be found; the check
+	// is skipped.
+	LighthouseNotInstalled = "not_installed"
+	// LighthouseChromeLaunch means Lighthouse could not start or connect to
+	// Chrome.
+	LighthouseChromeLaunch = "chrome_launch"
+	// LighthouseNavigation means the page failed to load or paint.
+	LighthouseNavigation = "navigation"
+	// LighthouseTimeout means the run did not finish in time.
+	LighthouseTimeout = "timeout"
+	// LighthouseRuntime covers every other failure of Lighthouse.
+	LighthouseRuntime = "runtime"
+)
+
+// LighthousePage holds the scores of one audited page.
+type LighthousePage struct {
+	// URL is the page's path relative to the site root.
+	URL string `json:"url"`
+	// LighthouseScores holds the median of the runs' scores.
+	LighthouseScores
+	// Runs holds the scores of every run when the page was audited more
+	// than once, and Spread the difference between the highest and lowest
+	// score of each category.
+	Runs   []LighthouseScores `json:"runs,omitempty"`
+	Spread *LighthouseScores  `json:"spread,omitempty"`
+	// Unstable is set when the spread of a category exceeds the configured
+	// bound, so the scores should not be trusted.
+	Unstable bool `json:"unstable,omitempty"`
+	// Audits lists the failing and low-scoring audits of the page, from
+	// the run with the median performance score.
+	Audits []LighthouseAudit `json:"audits,omitempty"`
+	// Reports lists the Lighthouse reports kept for each run when an
+	// artifacts directory is configured.
+	Reports []LighthouseReport `json:"reports,omitempty"`
+	// Error is set when the page could not be audited, and ErrorKind
+	// classifies it.
+	Error     string `json:"error,omitempty"`
+	ErrorKind string `json:"error_kind,omitempty"`
+}
+
+// LighthouseReport holds the paths of the full Lighthouse reports of a run.
+type LighthouseReport struct {
+	Run  int    `json:"run"`
+	JSON string `json:"json"`
+	HTML string `json:"html"`
+}
+
+// LighthouseScores holds the category scores of a Lighthouse run, from 0 to
+// 100.
+type LighthouseScores struct {
+	Performance   int `json:"performance"`
+	Accessibility int `json:"accessibility"`
+	SEO           int `json:"seo"`
+	BestPractices int `json:"best_practices,omitempty"`
+	PWA           int `json:"pwa,omitempty"`
+	// Metrics holds the lab metrics measured by the run.
+	Metrics *LighthouseMetrics `json:"metrics,omitempty"`
+}
+
+// LighthouseAudit is a Lighthouse audit that scored below 0.9, the score
+// Lighthouse itself shows as passing.
+type LighthouseAudit struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Category string `json:"category"`
+	// Weight is the audit's weight in its category score.
+	Weight       float64 `json:"weight"`
+	Score        float64 `json:"score"`
+	DisplayValue string  `json:"display_value,omitempty"`
+	// Items lists the selectors of the offending nodes or the URLs of the
+	// offending resources, and Pages the pages the audit failed on.
+	Items []string `json:"items,omitempty"`
+	Pages []string `json:"pages,omitempty"`
+}
+
+// Category describes a Lighthouse category.
+type Category struct {
+	// ID is the id of the category in Lighthouse and in the config.
+	ID        string
+	Label     string
+	Score     func(*LighthouseScores) *int
+	Threshold func(*Thresholds) *int
+}
+
+// Categories lists the Lighthouse categories site-forge can gate on.
+var Categories = []Category{
+	{"performance", "Perf", func(s *LighthouseScores) *int { return &s.Performance }, func(t *Thresholds) *int { return &t.Performance }},
+	{"accessibility", "A11y", func(s *LighthouseScores) *int { return &s.Accessibility }, func(t *Thresholds) *int { return &t.Accessibility }},
+	{"seo", "SEO", func(s *LighthouseScores) *int { return &s.SEO }, func(t *Thresholds) *int { return &t.SEO }},
+	{"best-practices", "BP", func(s *LighthouseScores) *int { return &s.BestPractices }, func(t *Thresholds) *int { return &t.BestPractices }},
+	{"pwa", "PWA", func(s *LighthouseScores) *int { return &s.PWA }, func(t *Thresholds) *int { return &t.PWA }},
+}
+
+// LookupCategory returns the category with the given id.
+func LookupCategory(id string) (Category, bool) {
+	for _, c := range Categories {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// LighthouseMetrics holds the Core Web Vitals and other lab metrics of a
+// Lighthouse run. Times are in milliseconds.
+type LighthouseMetrics struct {
+	LCP             float64 `json:"lcp_ms"`
+	CLS             float64 `json:"cls"`
+	TBT             float64 `json:"tbt_ms"`
+	FCP             float64 `json:"fcp_ms"`
+	SpeedIndex      float64 `json:"speed_index_ms"`
+	TTI             float64 `json:"tti_ms"`
+	TotalByteWeight float64 `json:"total_byte_weight"`
+	// Missing lists the keys of the metrics Lighthouse did not report,
+	// whose fields are left at 0.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Has reports whether the metric with the given key was measured. A nil
+// m measured none.
+func (m *LighthouseMetrics) Has(key string) bool {
+	return m != nil && !slices.Contains(m.Missing, key)
+}
+
+// Metric describes a field of LighthouseMetrics.
+type Metric struct {
+	// Key is the name of the metric in budgets, such as "lcp".
+	Key   string
+	Label string
+	// Audit is the id of the Lighthouse audit that measures the metric.
+	Audit string
+	// Unit is "ms", "bytes" or "" for unitless metrics.
+	Unit  string
+	Field func(*LighthouseMetrics) *float64
+}
+
+// Metrics lists the metrics of LighthouseMetrics.
+var Metrics = []Metric{
+	{"lcp", "LCP", "largest-contentful-paint", "ms", func(m *LighthouseMetrics) *float64 { return &m.LCP }},
+	{"cls", "CLS", "cumulative-layout-shift", "", func(m *LighthouseMetrics) *float64 { return &m.CLS }},
+	{"tbt", "TBT", "total-blocking-time", "ms", func(m *LighthouseMetrics) *float64 { return &m.TBT }},
+	{"fcp", "FCP", "first-contentful-paint", "ms", func(m *LighthouseMetrics) *float64 { return &m.FCP }},
+	{"speed_index", "Speed Index", "speed-index", "ms", func(m *LighthouseMetrics) *float64 { return &m.SpeedIndex }},
+	{"tti", "TTI", "interactive", "ms", func(m *LighthouseMetrics) *float64 { return &m.TTI }},
+	{"total_byte_weight", "Total byte weight", "total-byte-weight", "bytes", func(m *LighthouseMetrics) *float64 { return &m.TotalByteWeight }},
+}
+
+// Format returns v in the metric's unit, such as "2.5 s", "120 ms",
+// "0.12" or "1.6 MB".
+func (m Metric) Format(v float64) string {
+	switch m.Unit {
+	case "ms":
+		if v >= 1000 {
+			return fmt.Sprintf("%.1f s", v/1000)
+		}
+		return fmt.Sprintf("%.0f ms", v)
+	case "bytes":
+		switch {
+		case v >= 1e6:
+			return fmt.Sprintf("%.1f MB", v/1e6)
+		case v >= 1e3:
+			return fmt.Sprintf("%.0f KB", v/1e3)
+		}
+		return fmt.Sprintf("%.0f B", v)
+	}
+	return fmt.Sprintf("%.3g", v)
+}
+
+// MetricBudget is the outcome of a configured metric budget. A budget
+// whose metric was not measured is Missing and does not pass.
+type MetricBudget struct {
+	Metric  string  `json:"metric"`
+	Budget  float64 `json:"budget"`
+	Value   float64 `json:"value"`
+	Pass    bool    `json:"pass"`
+	Missing bool    `json:"missing,omitempty"`
+}
+
+func (r LighthouseResult) CheckStatus() string { return r.Status }
+
+func (r LighthouseResult) Summary() string {
+	if len(r.FormFactors) > 0 {
+		var parts []string
+		for _, ff := range r.FormFactors {
+			parts = append(parts, ff.FormFactor+": "+ff.Summary())
+		}
+		return strings.Join(parts, "; ")
+	}
+	var parts []string
+	for _, c := range r.categories() {
+		parts = append(parts, fmt.Sprintf("%s %d", c.Label, *c.Score(&r.LighthouseScores)))
+	}
+	scores := strings.Join(parts, " | ")
+	if len(r.Pages) > 1 {
+		scores += fmt.Sprintf(" (%s of %d pages)", r.Aggregate, len(r.Pages))
+	}
+	if r.Unstable {
+		scores += " [unstable]"
+	}
+	switch r.Status {
+	case StatusPass:
+		return scores
+	case StatusFail:
+		if len(r.Pages) == 0 {
+			return "FAIL - " + r.Details
+		}
+		reason := "thresholds not met"
+		if over := r.overBudget(); len(over) > 0 {
+			reason = "over budget: " + strings.Join(over, ", ")
+			if r.belowThresholds() {
+				reason = "thresholds not met, " + reason
+			}
+		}
+		if failed := r.failedPages(); failed > 0 {
+			reason = fmt.Sprintf("%d pages could not be audited", failed)
+		}
+		if worst, ok := r.worstPage(); ok && len(r.Pages) > 1 {
+			reason += ", worst page " + worst.URL
+		}
+		return fmt.Sprintf("%s (%s)", scores, reason)
+	}
+	return r.Details
+}
+
+// maxDetailAudits is the number of audits listed by DetailLines.
+const maxDetailAudits = 5
+
+// DetailLines lists the top offending audits of a failed result.
+func (r LighthouseResult) DetailLines() []string {
+	if len(r.FormFactors) > 0 {
+		var lines []string
+		for _, ff := range r.FormFactors {
+			for _, line := range ff.DetailLines() {
+				lines = append(lines, ff.FormFactor+" "+line)
+			}
+		}
+		return lines
+	}
+	if r.Status != StatusFail || len(r.Audits) == 0 {
+		return nil
+	}
+	var lines []string
+	for i, a := range r.Audits {
+		if i == maxDetailAudits {
+			lines = append(lines, fmt.Sprintf("... and %d more audits", len(r.Audits)-i))
+			break
+		}
+		label := a.Category
+		if c, ok := LookupCategory(a.Category); ok {
+			label = c.Label
+		}
+		line := fmt.Sprintf("- [%s] %s: %s", label, a.ID, a.Title)
+		if a.DisplayValue != "" {
+			line += " (" + a.DisplayValue + ")"
+		}
+		if len(a.Items) > 0 {
+			items := a.Items
+			if len(items) > 3 {
+				items = append(items[:3:3], fmt.Sprintf("+%d more", len(a.Items)-3))
+			}
+			line += " - " + strings.Join(items, ", ")
+		}
+		if len(r.Pages) > 1 && len(a.Pages) > 0 {
+			line += " on " + strings.Join(a.Pages, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// categories returns the audited categories.
+func (r LighthouseResult) categories() []Category {
+	if len(r.Categories) == 0 {
+		return Categories[:3]
+	}
+	var cats []Category
+	for _, id := range r.Categories {
+		if c, ok := LookupCategory(id); ok {
+			cats = append(cats, c)
+		}
+	}
+	return cats
+}
+
+// overBudget describes the metrics that exceeded their budget, such as
+// "LCP 3.1 s > 2.5 s", or were not measured.
+func (r LighthouseResult) overBudget() []string {
+	var over []string
+	for _, b := range r.Budgets {
+		if b.Pass {
+			continue
+		}
+		for _, m := range Metrics {
+			if m.Key != b.Metric {
+				continue
+			}
+			if b.Missing {
+				over = append(over, m.Label+" not measured")
+			} else {
+				over = append(over, fmt.Sprintf("%s %s > %s", m.Label, m.Format(b.Value), m.Format(b.Budget)))
+			}
+		}
+	}
+	return over
+}
+
+// belowThresholds reports whether an audited category scores below its
+// threshold.
+func (r LighthouseResult) belowThresholds() bool {
+	for _, c := range r.categories() {
+		if *c.Score(&r.LighthouseScores) < *c.Threshold(&r.Thresholds) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r LighthouseResult) failedPages() int {
+	n := 0
+	for _, p := range r.Pages {
+		if p.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// worstPage returns the audited page with the lowest score in any category,
+// preferring pages that could not be audited.
+func (r LighthouseResult) worstPage() (LighthousePage, bool) {
+	var worst LighthousePage
+	lowest := 101
+	for _, p := range r.Pages {
+		low := 100
+		for _, c := range r.categories() {
+			low = min(low, *c.Score(&p.LighthouseScores))
+		}
+		if p.Error != "" {
+			low = -1
+		}
+		if low < lowest {
+			worst, lowest = p, low
+		}
+	}
+	return worst, lowest <= 100
+}
+
+type Thresholds struct {
+	Performance   int `json:"performance"`
+	Accessibility int `json:"accessibility"`
+	SEO           int `json:"seo"`
+	BestPractices int `json:"best_practices,omitempty"`
+	PWA           int `json:"pwa,omitempty"`
+}
+
+type ScreenshotsResult struct {
+	Status string `json:"status"`
+	// Pages indexes the captures by page, in the order they were taken.
+	Pages []ScreenshotPage `json:"pages,omitempty"`
+	// Browser is the product and version of the browser that captured
+	// the screenshots, such as "HeadlessChrome/126.0.6478.126".
+	Browser string `json:"browser,omitempty"`
+	Details string `json:"details,omitempty"`
+}
+
+// ScreenshotPage holds the screenshots of one page.
+type ScreenshotPage struct {
+	// URL is the page's path relative to the site root.
+	URL string `json:"url"`
+	// Viewports maps the name of each viewport to its screenshot.
+	Viewports map[string]string `json:"viewports,omitempty"`
+	// Readiness maps the name of each captured viewport to the wait for the
+	// page to be ready in it.
+	Readiness map[string]Readiness `json:"readiness,omitempty"`
+	// Error is set when a viewport could not be captured.
+	Error string `json:"error,omitempty"`
+}
+
+// Readiness records the wait for a page to be ready to be captured.
+type Readiness struct {
+	// Conditions lists the conditions awaited, in order.
+	Conditions []ReadyCondition `json:"conditions,omitempty"`
+	// Wait is the time from the page load to the capture.
+	Wait float64 `json:"wait_ms"`
+}
+
+// ReadyCondition is the outcome of one readiness condition.
+type ReadyCondition struct {
+	Name string `json:"name"`
+	Met  bool   `json:"met"`
+	// Wait is the time from the page load until the condition held, or
+	// until it was given up on.
+	Wait float64 `json:"wait_ms"`
+}
+
+// Pending lists the conditions that did not hold.
+func (r Readiness) Pending() []string {
+	var names []string
+	for _, c := range r.Conditions {
+		if !c.Met {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+func (r ScreenshotsResult) CheckStatus() string { return r.Status }
+
+func (r ScreenshotsResult) Summary() string {
+	switch r.Status {
+	case StatusPass:
+		return r.captured()
+	case StatusFail:
+		return "FAIL - " + r.Details
+	}
+	return r.Details
+}
+
+// DetailLines lists the captures taken before the page was ready.
+func (r ScreenshotsResult) DetailLines() []string {
+	var lines []string
+	for _, p := range r.Pages {
+		var names []string
+		for name := range p.Readiness {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ready := p.Readiness[name]
+			if pending := ready.Pending(); len(pending) > 0 {
+				wait := time.Duration(ready.Wait * float64(time.Millisecond)).Round(time.Millisecond)
+				lines = append(lines, fmt.Sprintf("- %s (%s): captured after %v without %s", p.URL, name, wait, strings.Join(pending, ", ")))
+			}
+		}
+	}
+	return lines
+}
+
+// captured describes the captures, such as "3 pages x 2 viewports
+// captured (desktop, mobile)".
+func (r ScreenshotsResult) captured() string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range r.Pages {
+		for name := range p.Viewports {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%d pages x %d viewports captured (%s)", len(r.Pages), len(names), strings.Join(names, ", "))
+}
+
+type VisualResult struct {
+	Status string `json:"status"`
+	// MaxDiffRatio is the largest share of the pixels of a capture that may
+	// differ from its baseline.
+	MaxDiffRatio float64 `json:"max_diff_ratio"`
+	// Captures lists the comparisons in the order of the screenshots.
+	Captures []VisualCapture `json:"captures,omitempty"`
+	Details  string          `json:"details,omitempty"`
+}
+
+// VisualCapture is the comparison of one screenshot with its baseline.
+type VisualCapture struct {
+	URL        string `json:"url"`
+	Viewport   string `json:"viewport"`
+	Screenshot string `json:"screenshot"`
+	// Baseline is the screenshot compared against, empty if there is none.
+	Baseline string `json:"baseline,omitempty"`
+	// DiffPixels is the number of pixels that differ, and DiffRatio their
+	// share of the pixels of the larger image.
+	DiffPixels int     `json:"diff_pixels"`
+	DiffRatio  float64 `json:"diff_ratio"`
+	// Diff is the image highlighting the differences, written when there
+	// are any.
+	Diff string `json:"diff,omitempty"`
+	// Error is set when the images could not be compared.
+	Error string `json:"error,omitempty"`
+}
+
+// Failed reports whether c could not be compared or differs by more than
+// maxRatio.
+func (c VisualCapture) Failed(maxRatio float64) bool {
+	return c.Error != "" || c.DiffRatio > maxRatio
+}
+
+func (r VisualResult) CheckStatus() string { return r.Status }
+
+func (r VisualResult) Summary() string {
+	compared, failed := 0, 0
+	largest := 0.0
+	for _, c := range r.Captures {
+		if c.Baseline == "" && c.Error == "" {
+			continue
+		}
+		compared++
+		if c.Failed(r.MaxDiffRatio) {
+			failed++
+		}
+		largest = max(largest, c.DiffRatio)
+	}
+	switch r.Status {
+	case StatusPass:
+		return fmt.Sprintf("%d captures within %s of the baseline (largest difference: %s)", compared, percent(r.MaxDiffRatio), percent(largest))
+	case StatusFail:
+		if failed == 0 {
+			return "FAIL - " + r.Details
+		}
+		return fmt.Sprintf("%d of %d captures differ from the baseline by more than %s", failed, compared, percent(r.MaxDiffRatio))
+	}
+	return r.Details
+}
+
+// DetailLines lists the captures that failed or have no baseline.
+func (r VisualResult) DetailLines() []string {
+	var lines []string
+	for _, c := range r.Captures {
+		prefix := fmt.Sprintf("- %s (%s): ", c.URL, c.Viewport)
+		switch {
+		case c.Error != "":
+			lines = append(lines, prefix+c.Error)
+		case c.Baseline == "":
+			lines = append(lines, prefix+"no baseline")
+		case c.Failed(r.MaxDiffRatio):
+			lines = append(lines, fmt.Sprintf("%s%s of pixels differ, see %s", prefix, percent(c.DiffRatio), c.Diff))
+		}
+	}
+	return lines
+}
+
+// percent formats ratio as a percentage with up to two decimals, such as
+// "0.25%".
+func percent(ratio float64) string {
+	return strconv.FormatFloat(math.Round(ratio*1e4)/100, 'f', -1, 64) + "%"
+}
+
+type VisionResult struct {
+	Status    string `json:"status"`
+	Score     int    `json:"score,omitempty"`
+	Threshold int    `json:"threshold"`
+	Analysis  string `json:"analysis,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+func (r VisionResult) CheckStatus() string { return r.Status }
+
+func (r VisionResult) Summary() string {
+	switch r.Status {
+	case StatusPass:
+		return fmt.Sprintf("Score %d/10 (threshold: %d)", r.Score, r.Threshold)
+	case StatusFail:
+		return fmt.Sprintf("Score %d/10 (threshold: %d) - %s", r.Score, r.Threshold, r.Analysis)
+	}
+	return r.Details
+}
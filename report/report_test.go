@@ -0,0 +1,211 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReportJSONRoundTrip(t *testing.T) {
+	r := NewReport("dist")
+	r.Overall = StatusFail
+	r.Add("vision", VisionResult{Status: StatusSkip, Details: "No baseline provided", Threshold: 7})
+	r.Add("assets", AssetsResult{Status: StatusFail, Total: 3, Unique: 3, Missing: []AssetFinding{{URL: "hero.jpg", Source: "index.html", Line: 4, Column: 7}}})
+	r.Add("robots", BasicResult{Status: StatusBlocked, Details: "requires build"})
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var got Report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	names := got.Names()
+	if len(names) != 3 || names[0] != "vision" || names[1] != "assets" || names[2] != "robots" {
+		t.Errorf("Expected checks in run order, got %v", names)
+	}
+	assets, ok := got.Checks["assets"].(AssetsResult)
+	if !ok {
+		t.Fatalf("Expected AssetsResult, got %T", got.Checks["assets"])
+	}
+	if len(assets.Missing) != 1 || assets.Missing[0].Location() != "index.html:4:7" {
+		t.Errorf("Expected missing hero.jpg, got %v", assets.Missing)
+	}
+	if got.Checks["robots"].CheckStatus() != StatusBlocked {
+		t.Errorf("Expected robots BLOCKED, got %s", got.Checks["robots"].CheckStatus())
+	}
+	if got.FormatSummary() != r.FormatSummary() {
+		t.Errorf("Expected identical summaries, got:\n%s\nwant:\n%s", got.FormatSummary(), r.FormatSummary())
+	}
+}
+
+func TestLighthouseResultSummary(t *testing.T) {
+	r := LighthouseResult{
+		Status:           StatusFail,
+		LighthouseScores: LighthouseScores{Performance: 61, Accessibility: 95, SEO: 92},
+		Aggregate:        "min",
+		Unstable:         true,
+		Pages: []LighthousePage{
+			{URL: "/", LighthouseScores: LighthouseScores{Performance: 98, Accessibility: 100, SEO: 100}},
+			{
+				URL:              "/blog/",
+				LighthouseScores: LighthouseScores{Performance: 61, Accessibility: 95, SEO: 92},
+				Runs: []LighthouseScores{
+					{Performance: 55, Accessibility: 95, SEO: 92},
+					{Performance: 61, Accessibility: 95, SEO: 92},
+					{Performance: 70, Accessibility: 95, SEO: 92},
+				},
+				Spread:   &LighthouseScores{Performance: 15},
+				Unstable: true,
+			},
+		},
+	}
+	want := "Perf 61 | A11y 95 | SEO 92 (min of 2 pages) [unstable] (thresholds not met, worst page /blog/)"
+	if got := r.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(r.Pages[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var page map[string]interface{}
+	json.Unmarshal(data, &page)
+	if page["performance"] != float64(61) || page["url"] != "/blog/" {
+		t.Errorf("Expected page scores at the top level, got %s", data)
+	}
+
+	r = LighthouseResult{
+		Status:           StatusFail,
+		LighthouseScores: LighthouseScores{Performance: 95, Accessibility: 95, SEO: 95},
+		Thresholds:       Thresholds{Performance: 90, Accessibility: 90, SEO: 90},
+		Pages:            []LighthousePage{{URL: "/"}},
+		Budgets: []MetricBudget{
+			{Metric: "lcp", Budget: 2500, Value: 3120, Pass: false},
+			{Metric: "cls", Budget: 0.1, Value: 0.02, Pass: true},
+			{Metric: "tbt", Budget: 200, Missing: true},
+		},
+	}
+	want = "Perf 95 | A11y 95 | SEO 95 (over budget: LCP 3.1 s > 2.5 s, TBT not measured)"
+	if got := r.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	r = LighthouseResult{
+		Status:           StatusFail,
+		Categories:       []string{"performance", "best-practices"},
+		LighthouseScores: LighthouseScores{Performance: 95, BestPractices: 83},
+		Thresholds:       Thresholds{Performance: 90, BestPractices: 90},
+		Pages:            []LighthousePage{{URL: "/"}},
+	}
+	want = "Perf 95 | BP 83 (thresholds not met)"
+	if got := r.Summary(); got != want || !r.belowThresholds() {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	r.Audits = []LighthouseAudit{{
+		ID:       "errors-in-console",
+		Title:    "Browser errors were logged to the console",
+		Category: "best-practices",
+		Items:    []string{"/app.js", "/vendor.js", "/a.js", "/b.js"},
+	}}
+	rep := NewReport("dist")
+	rep.Add("lighthouse", r)
+	want = "       - [BP] errors-in-console: Browser errors were logged to the console - /app.js, /vendor.js, /a.js, +1 more\n"
+	if !strings.Contains(rep.FormatSummary(), want) {
+		t.Errorf("Expected summary to list the failing audit, got:\n%s", rep.FormatSummary())
+	}
+}
+
+func TestLighthouseResultFormFactors(t *testing.T) {
+	r := LighthouseResult{
+		Status: StatusFail,
+		FormFactors: []LighthouseResult{
+			{
+				Status:           StatusPass,
+				FormFactor:       "mobile",
+				LighthouseScores: LighthouseScores{Performance: 91, Accessibility: 95, SEO: 92},
+				Thresholds:       Thresholds{Performance: 90, Accessibility: 90, SEO: 90},
+				Pages:            []LighthousePage{{URL: "/"}},
+			},
+			{
+				Status:           StatusFail,
+				FormFactor:       "desktop",
+				LighthouseScores: LighthouseScores{Performance: 88, Accessibility: 95, SEO: 92},
+				Thresholds:       Thresholds{Performance: 95, Accessibility: 90, SEO: 90},
+				Pages:            []LighthousePage{{URL: "/"}},
+				Audits:           []LighthouseAudit{{ID: "unused-javascript", Title: "Reduce unused JavaScript", Category: "performance"}},
+			},
+		},
+	}
+	want := "mobile: Perf 91 | A11y 95 | SEO 92; desktop: Perf 88 | A11y 95 | SEO 92 (thresholds not met)"
+	if got := r.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	lines := r.DetailLines()
+	if len(lines) != 1 || lines[0] != "desktop - [Perf] unused-javascript: Reduce unused JavaScript" {
+		t.Errorf("DetailLines() = %q", lines)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got LighthouseResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.FormFactors) != 2 || got.FormFactors[1].Thresholds.Performance != 95 {
+		t.Errorf("Expected form factors to round-trip, got %s", data)
+	}
+}
+
+func TestScreenshotsResultSummary(t *testing.T) {
+	r := ScreenshotsResult{Status: StatusPass, Pages: []ScreenshotPage{
+		{URL: "/", Viewports: map[string]string{"wide": "screenshots/index.wide.png", "desktop": "screenshots/index.desktop.png"}},
+		{URL: "/pricing/", Viewports: map[string]string{"tablet": "screenshots/pricing.tablet.png", "desktop": "screenshots/pricing.desktop.png"}},
+	}}
+	if got, want := r.Summary(), "2 pages x 3 viewports captured (desktop, tablet, wide)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestScreenshotsResultDetailLines(t *testing.T) {
+	ready := Readiness{Conditions: []ReadyCondition{{Name: "network_idle", Met: true, Wait: 620}, {Name: "fonts", Met: true, Wait: 640}}, Wait: 640}
+	late := Readiness{Conditions: []ReadyCondition{{Name: "network_idle", Met: true, Wait: 700}, {Name: "fonts", Wait: 10000}, {Name: "images", Wait: 10000}}, Wait: 10000}
+	r := ScreenshotsResult{Status: StatusPass, Pages: []ScreenshotPage{
+		{URL: "/", Readiness: map[string]Readiness{"desktop": ready, "mobile": ready}},
+		{URL: "/blog/", Readiness: map[string]Readiness{"mobile": late, "desktop": ready}},
+	}}
+	lines := r.DetailLines()
+	if len(lines) != 1 || lines[0] != "- /blog/ (mobile): captured after 10s without fonts, images" {
+		t.Errorf("DetailLines() = %q", lines)
+	}
+}
+
+func TestVisualResultSummary(t *testing.T) {
+	r := VisualResult{Status: StatusFail, MaxDiffRatio: 0.01, Captures: []VisualCapture{
+		{URL: "/", Viewport: "desktop", Baseline: "baseline/index.desktop.png", DiffRatio: 0.0012},
+		{URL: "/", Viewport: "mobile", Baseline: "baseline/index.mobile.png", DiffRatio: 0.0342, Diff: "screenshots/diff/index.mobile.png"},
+		{URL: "/new/", Viewport: "desktop"},
+	}}
+	if got, want := r.Summary(), "1 of 2 captures differ from the baseline by more than 1%"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	lines := r.DetailLines()
+	want := []string{
+		"- / (mobile): 3.42% of pixels differ, see screenshots/diff/index.mobile.png",
+		"- /new/ (desktop): no baseline",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DetailLines() = %q, want %q", lines, want)
+	}
+
+	r.Status, r.Captures = StatusPass, r.Captures[:1]
+	if got, want := r.Summary(), "1 captures within 1% of the baseline (largest difference: 0.12%)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}